@@ -2,36 +2,43 @@ package api
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
-	"net/http/httputil"
 	"net/url"
+	"path"
+	"regexp"
 	"strings"
 
 	"golang.org/x/net/html"
 )
 
-func login(ctx context.Context, u *url.URL) {
-	loginURL, err := u.Parse("login")
+// ErrNotLoggedIn is returned when a request needs a logged-in session.
+var ErrNotLoggedIn = errors.New("not logged in")
+
+// userScreenNamePattern finds the user name embedded in every page as
+// `var userScreenName = "...";`. It is empty if not logged in.
+var userScreenNamePattern = regexp.MustCompile(`userScreenName\s*=\s*"([^"]*)"`)
+
+// Login logs in with the credentials. The session cookie is kept by the
+// session given by WithSession.
+func (c *Client) Login(ctx context.Context, username, password string) error {
+	loginURL, err := c.baseURL.Parse("login")
 	if err != nil {
-		slog.ErrorContext(ctx, "failed to parse login URL", slog.String("base_url", u.String()), slog.String("err", err.Error()))
-		return
+		return err
 	}
 
-	csrfToken, err := findCSRFToken(ctx, loginURL)
+	csrfToken, err := c.findCSRFToken(ctx, loginURL)
 	if err != nil {
 		slog.ErrorContext(ctx, "failed to find CSRF token", slog.String("url", loginURL.String()), slog.String("err", err.Error()))
-		return
+		return err
 	}
-	fmt.Println("CSRF Token:", csrfToken)
-
-	// クエリパラメータの設定例
 
 	q := url.Values{}
-	q.Add("username", "****") // TODO: 外から受け取る
-	q.Add("password", "****") // TODO: 外から受け取る
+	q.Add("username", username)
+	q.Add("password", password)
 	q.Add("csrf_token", csrfToken)
 
 	req, err := http.NewRequestWithContext(
@@ -39,55 +46,80 @@ func login(ctx context.Context, u *url.URL) {
 		strings.NewReader(q.Encode()),
 	)
 	if err != nil {
-		slog.ErrorContext(ctx, "failed to create login POST request", slog.String("url", loginURL.String()), slog.String("err", err.Error()))
-		return
+		return err
 	}
-	dump, err := httputil.DumpRequestOut(req, true)
-	if err == nil {
-		slog.ErrorContext(ctx, "response message", slog.String("response", string(dump)))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := c.do(req)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to send login request", slog.String("url", loginURL.String()), slog.String("err", err.Error()))
+		return err
 	}
-	resp, err := http.DefaultClient.Do(req)
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	// 失敗した場合もログインページにリダイレクトされるだけなので、ログイン状態を確認する
+	user, err := c.Whoami(ctx)
 	if err != nil {
-		slog.ErrorContext(ctx, "failed to send login POST request", slog.String("url", loginURL.String()), slog.String("err", err.Error()))
-		return
+		return err
+	}
+	if !strings.EqualFold(user, username) {
+		return fmt.Errorf("logged in as %q instead of %q", user, username)
+	}
+	return nil
+}
+
+// Whoami returns the name of the logged-in user, or ErrNotLoggedIn.
+func (c *Client) Whoami(ctx context.Context) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := c.do(req)
+	if err != nil {
+		return "", err
 	}
 	defer resp.Body.Close()
-	dump, err = httputil.DumpResponse(resp, true)
-	if err == nil {
-		slog.ErrorContext(ctx, "response message", slog.String("response", string(dump)))
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	m := userScreenNamePattern.FindSubmatch(body)
+	if m == nil || len(m[1]) == 0 {
+		return "", ErrNotLoggedIn
 	}
+	return string(m[1]), nil
 }
-func findCSRFToken(ctx context.Context, loginURL *url.URL) (string, error) {
-	req, err := http.NewRequestWithContext(ctx, "GET", loginURL.String(), nil)
+
+func (c *Client) findCSRFToken(ctx context.Context, pageURL *url.URL) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", pageURL.String(), nil)
 	if err != nil {
-		slog.ErrorContext(ctx, "failed to create login request", slog.String("url", loginURL.String()), slog.String("err", err.Error()))
+		slog.ErrorContext(ctx, "failed to create request", slog.String("url", pageURL.String()), slog.String("err", err.Error()))
 		return "", err
 	}
-	client := http.DefaultClient
-	resp, err := client.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
-		slog.ErrorContext(ctx, "failed to send login request", slog.String("url", loginURL.String()), slog.String("err", err.Error()))
+		slog.ErrorContext(ctx, "failed to send request", slog.String("url", pageURL.String()), slog.String("err", err.Error()))
 		return "", err
 	}
 	defer resp.Body.Close()
 	defer io.Copy(io.Discard, resp.Body)
 
-	if resp.StatusCode != http.StatusOK {
-		slog.ErrorContext(ctx, "login request failed", slog.String("url", loginURL.String()), slog.Int("status_code", resp.StatusCode))
-		return "", err
+	// ログインが必要なページはログインページにリダイレクトされる
+	if resp.Request.URL.Path != pageURL.Path && path.Base(resp.Request.URL.Path) == "login" {
+		return "", ErrNotLoggedIn
 	}
 
 	node, err := html.ParseWithOptions(resp.Body)
 	if err != nil {
 		slog.ErrorContext(
 			ctx,
-			"failed to parse login response",
-			slog.String("url", loginURL.String()),
+			"failed to parse response",
+			slog.String("url", pageURL.String()),
 			slog.String("err", err.Error()),
 		)
 		return "", err
 	}
-	fmt.Println(node.Type)
 	csrfToken, err := findOneNode(node, func(n *html.Node) bool {
 		return n.Type == html.ElementNode && n.Data == "input" && attrIs(n, "name", "csrf_token") && !attrIs(n, "value", "")
 	})
@@ -95,12 +127,11 @@ func findCSRFToken(ctx context.Context, loginURL *url.URL) (string, error) {
 		slog.ErrorContext(
 			ctx,
 			"failed to find csrf_token input element",
-			slog.String("url", loginURL.String()),
+			slog.String("url", pageURL.String()),
 			slog.String("err", err.Error()),
 		)
 		return "", err
 	}
-	fmt.Println(csrfToken.Data)
 	for _, a := range csrfToken.Attr {
 		if a.Key == "value" && a.Val != "" {
 			return a.Val, nil
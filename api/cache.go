@@ -0,0 +1,222 @@
+package api
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ErrOffline is returned for a request which cannot be served from the cache
+// in the offline mode.
+var ErrOffline = errors.New("not available offline")
+
+// DefaultCacheMaxAge is how long a cached page without validators (ETag or
+// Last-Modified) is used without asking the server.
+const DefaultCacheMaxAge = time.Hour
+
+// Cache keeps responses on disk, keyed by the URL and the logged-in user.
+type Cache struct {
+	dir    string
+	maxAge time.Duration
+}
+
+// NewCache creates a cache keeping the responses in dir.
+func NewCache(dir string) *Cache {
+	return &Cache{dir: dir, maxAge: DefaultCacheMaxAge}
+}
+
+// SetMaxAge sets how long a cached page without validators is used without
+// asking the server.
+func (c *Cache) SetMaxAge(maxAge time.Duration) {
+	c.maxAge = maxAge
+}
+
+type cacheEntry struct {
+	URL          string    `json:"url"`
+	User         string    `json:"user,omitempty"`
+	StoredAt     time.Time `json:"stored_at"`
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"last_modified,omitempty"`
+	ContentType  string    `json:"content_type,omitempty"`
+	Body         []byte    `json:"body"`
+}
+
+func (e *cacheEntry) hasValidators() bool {
+	return e.ETag != "" || e.LastModified != ""
+}
+
+// response makes a response to the request from the entry.
+func (e *cacheEntry) response(req *http.Request) *http.Response {
+	header := http.Header{}
+	if e.ContentType != "" {
+		header.Set("Content-Type", e.ContentType)
+	}
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+		Request:       req,
+	}
+}
+
+func (c *Cache) path(url, user string) string {
+	sum := sha256.Sum256([]byte(user + "\n" + url))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+func (c *Cache) load(url, user string) (*cacheEntry, error) {
+	data, err := os.ReadFile(c.path(url, user))
+	if err != nil {
+		return nil, err
+	}
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+func (c *Cache) store(entry *cacheEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(c.dir, 0700); err != nil {
+		return err
+	}
+	// 書き込み途中のファイルを読まないように置き換える
+	f, err := os.CreateTemp(c.dir, "entry-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), c.path(entry.URL, entry.User))
+}
+
+// WithCache makes the client keep the pages which rarely change, such as task
+// pages, in the cache and revalidate them with conditional requests.
+func WithCache(cache *Cache) ClientOption {
+	return func(c *Client) {
+		c.cache = cache
+	}
+}
+
+// WithOffline makes the client serve the pages only from the cache. Other
+// requests fail with ErrOffline.
+func WithOffline() ClientOption {
+	return func(c *Client) {
+		c.offline = true
+	}
+}
+
+// WithRefresh makes the client ask the server for every page, so that a
+// page changed since it was cached, e.g. a statement fixed during the
+// contest, is fetched again. Pages not modified are still served from the
+// cache.
+func WithRefresh() ClientOption {
+	return func(c *Client) {
+		c.refresh = true
+	}
+}
+
+// cacheUser returns the user the cached pages are kept for, since a page may
+// differ for the logged-in user.
+func (c *Client) cacheUser() string {
+	if session, ok := c.httpClient.Jar.(*Session); ok {
+		if user, ok := session.User(); ok {
+			return user
+		}
+	}
+	return ""
+}
+
+// doCached sends the GET request through the cache.
+func (c *Client) doCached(req *http.Request) (*http.Response, error) {
+	if c.cache == nil {
+		return c.do(req)
+	}
+
+	ctx := req.Context()
+	url, user := req.URL.String(), c.cacheUser()
+	entry, err := c.cache.load(url, user)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		slog.WarnContext(ctx, "ignored broken cache entry", slog.String("url", url), slog.String("err", err.Error()))
+	}
+	if c.offline {
+		if entry == nil {
+			return nil, fmt.Errorf("%s: %w", url, ErrOffline)
+		}
+		return entry.response(req), nil
+	}
+	if entry != nil && !c.refresh && !entry.hasValidators() && time.Since(entry.StoredAt) < c.cache.maxAge {
+		slog.DebugContext(ctx, "using cached page", slog.String("url", url))
+		return entry.response(req), nil
+	}
+	if entry != nil {
+		if entry.ETag != "" {
+			req.Header.Set("If-None-Match", entry.ETag)
+		}
+		if entry.LastModified != "" {
+			req.Header.Set("If-Modified-Since", entry.LastModified)
+		}
+	}
+
+	resp, err := c.do(req)
+	var statusErr *StatusError
+	if entry != nil && errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotModified {
+		slog.DebugContext(ctx, "cached page is not modified", slog.String("url", url))
+		entry.StoredAt = time.Now()
+		if err := c.cache.store(entry); err != nil {
+			slog.WarnContext(ctx, "failed to update cache entry", slog.String("url", url), slog.String("err", err.Error()))
+		}
+		return entry.response(req), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	// ログインページなどにリダイレクトされた場合は保存しない
+	if resp.Request.URL.String() != url {
+		return resp, nil
+	}
+	entry = &cacheEntry{
+		URL:          url,
+		User:         user,
+		StoredAt:     time.Now(),
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+		ContentType:  resp.Header.Get("Content-Type"),
+		Body:         body,
+	}
+	if err := c.cache.store(entry); err != nil {
+		slog.WarnContext(ctx, "failed to store cache entry", slog.String("url", url), slog.String("err", err.Error()))
+	}
+	return resp, nil
+}
@@ -1,8 +1,11 @@
 package api
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
+	"strings"
 	"sync"
 	"time"
 
@@ -10,76 +13,153 @@ import (
 )
 
 type Client struct {
-	family   contests.Family
-	interval time.Duration
+	family      contests.Family
+	limiter     *limiter
+	retryBudget time.Duration
+	baseURL     *url.URL
+	userAgent   string
+	httpClient  *http.Client
+	jar         http.CookieJar
+	transport   http.RoundTripper
+	proxy       *url.URL
+	cache       *Cache
+	offline     bool
+	refresh     bool
 
-	reqCh        chan *request
 	shutdownOnce sync.Once
 	shutdownCh   chan struct{}
 }
 
-type request struct {
-	req  *http.Request
-	resp chan *http.Response
-	err  chan error
+var errShutdown = errors.New("client is shut down")
+
+// ClientOption configures a Client.
+type ClientOption func(*Client)
+
+// WithSession makes the client send and keep the cookies of the session, so
+// that requests are made as the logged-in user.
+func WithSession(session *Session) ClientOption {
+	return func(c *Client) {
+		c.jar = session
+	}
+}
+
+// WithBaseURL makes the client send requests to the site at u instead of
+// DefaultBaseURL, e.g. a local stand-in for tests.
+func WithBaseURL(u *url.URL) ClientOption {
+	return func(c *Client) {
+		base := *u
+		if !strings.HasSuffix(base.Path, "/") {
+			base.Path += "/"
+		}
+		c.baseURL = &base
+	}
+}
+
+// WithHTTPClient makes the client send requests with hc, e.g. to set a
+// timeout. hc itself is not modified by the other options.
+func WithHTTPClient(hc *http.Client) ClientOption {
+	return func(c *Client) {
+		c.httpClient = hc
+	}
 }
 
-func NewClient(family contests.Family) *Client {
+// WithTransport makes the client send requests with the transport.
+func WithTransport(transport http.RoundTripper) ClientOption {
+	return func(c *Client) {
+		c.transport = transport
+	}
+}
+
+// WithProxy makes the client send requests through the proxy instead of the
+// one given by the environment (HTTPS_PROXY etc.). It has no effect on a
+// transport given by WithTransport other than *http.Transport.
+func WithProxy(proxy *url.URL) ClientOption {
+	return func(c *Client) {
+		c.proxy = proxy
+	}
+}
+
+// WithUserAgent sets the User-Agent header of the requests.
+func WithUserAgent(userAgent string) ClientOption {
+	return func(c *Client) {
+		c.userAgent = userAgent
+	}
+}
+
+// WithRetryBudget limits the time spent waiting to retry a request, after
+// which the last error is returned.
+func WithRetryBudget(budget time.Duration) ClientOption {
+	return func(c *Client) {
+		c.retryBudget = budget
+	}
+}
+
+// WithRateLimit limits the requests to rate per second on average, allowing
+// bursts of up to burst requests. Requests are sent concurrently within the
+// limit.
+func WithRateLimit(rate float64, burst int) ClientOption {
+	return func(c *Client) {
+		c.limiter = newLimiter(rate, burst)
+	}
+}
+
+// NewClient creates a new client for the contest of the family. family may be
+// nil for requests not bound to a contest.
+func NewClient(family contests.Family, opts ...ClientOption) *Client {
 	c := &Client{
-		family:   family,
-		interval: 100 * time.Millisecond,
+		family:      family,
+		limiter:     newLimiter(DefaultRate, DefaultBurst),
+		retryBudget: DefaultRetryBudget,
+		baseURL:     defaultBaseURL(),
+		userAgent:   DefaultUserAgent,
+		httpClient:  &http.Client{},
 
-		reqCh:        make(chan *request),
 		shutdownOnce: sync.Once{},
 		shutdownCh:   make(chan struct{}),
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
 
-	go c.requestLoop()
+	// 渡された http.Client を書き換えないようにコピーしてから設定する
+	httpClient := *c.httpClient
+	if c.jar != nil {
+		httpClient.Jar = c.jar
+	}
+	if c.proxy != nil {
+		transport, ok := c.transport.(*http.Transport)
+		if c.transport == nil {
+			transport, ok = http.DefaultTransport.(*http.Transport)
+		}
+		if ok {
+			transport = transport.Clone()
+			transport.Proxy = http.ProxyURL(c.proxy)
+			c.transport = transport
+		}
+	}
+	if c.transport != nil {
+		httpClient.Transport = c.transport
+	}
+	c.httpClient = &httpClient
 
 	return c
 }
 
 func (c *Client) Shutdown() {
-	c.shutdownOnce.Do(func() { close(c.shutdownCh); close(c.reqCh) })
+	c.shutdownOnce.Do(func() { close(c.shutdownCh) })
 }
 
 func (c *Client) do(req *http.Request) (*http.Response, error) {
-	respCh := make(chan *http.Response)
-	errCh := make(chan error)
-	c.reqCh <- &request{
-		req:  req,
-		resp: respCh,
-		err:  errCh,
+	if c.offline {
+		return nil, fmt.Errorf("%s: %w", req.URL, ErrOffline)
 	}
 	select {
-	case resp := <-respCh:
-		return resp, nil
-	case err := <-errCh:
-		return nil, err
 	case <-c.shutdownCh:
-		return nil, fmt.Errorf("client is shut down")
+		return nil, errShutdown
+	default:
 	}
-}
-
-func (c *Client) requestLoop() {
-	for {
-		select {
-		case req := <-c.reqCh:
-			resp, err := http.DefaultClient.Do(req.req)
-			if err != nil {
-				req.err <- err
-			} else {
-				req.resp <- resp
-			}
-			close(req.err)
-			close(req.resp)
-		case <-c.shutdownCh:
-			return
-		}
-		select {
-		case <-time.After(c.interval):
-		case <-c.shutdownCh:
-			return
-		}
+	if c.userAgent != "" && req.Header.Get("User-Agent") == "" {
+		req.Header.Set("User-Agent", c.userAgent)
 	}
+	return c.send(req)
 }
@@ -0,0 +1,133 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// testFamily is a contest family for a stand-in server.
+type testFamily string
+
+func (f testFamily) ContestName() string           { return string(f) }
+func (f testFamily) BaseDir(workdir string) string { return workdir }
+
+// newStandIn starts a stand-in server of the site. The top page shows the
+// user logged in with the session cookie "valid", and the task list of
+// abc350 has tasks A and B.
+func newStandIn(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", func(w http.ResponseWriter, r *http.Request) {
+		user := ""
+		if c, err := r.Cookie(SessionCookie); err == nil && c.Value == "valid" {
+			user = "foo"
+		}
+		fmt.Fprintf(w, `<script>var userScreenName = "%s";</script>`, user)
+	})
+	mux.HandleFunc("GET /contests/abc350/tasks", func(w http.ResponseWriter, r *http.Request) {
+		if ua := r.Header.Get("User-Agent"); ua != "stand-in-test" {
+			http.Error(w, "unexpected User-Agent "+ua, http.StatusBadRequest)
+			return
+		}
+		fmt.Fprint(w, `<table><tbody>
+<tr><td><a href="/contests/abc350/tasks/abc350_a">A</a></td><td>Welcome</td></tr>
+<tr><td><a href="/contests/abc350/tasks/abc350_b">B</a></td><td>Dentist</td></tr>
+</tbody></table>`)
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestWithBaseURL(t *testing.T) {
+	srv := newStandIn(t)
+	base, err := url.Parse(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := NewClient(testFamily("abc350"), WithBaseURL(base), WithUserAgent("stand-in-test"))
+	defer client.Shutdown()
+
+	tasks, err := client.FetchTaskList(context.Background())
+	if err != nil {
+		t.Fatalf("FetchTaskList: %v", err)
+	}
+	var got []string
+	for _, task := range tasks {
+		got = append(got, task.Index+" "+task.URL.String())
+	}
+	want := []string{
+		"A " + srv.URL + "/contests/abc350/tasks/abc350_a",
+		"B " + srv.URL + "/contests/abc350/tasks/abc350_b",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("tasks mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestImportSessionWithBaseURL(t *testing.T) {
+	srv := newStandIn(t)
+	base, err := url.Parse(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	host := base.Hostname()
+
+	tests := []struct {
+		name     string
+		cookies  string
+		wantUser string
+		// wantErr is where it fails: "parse" or "import".
+		wantErr string
+	}{
+		{
+			name:     "cookie of the stand-in",
+			cookies:  host + "\tFALSE\t/\tFALSE\t0\t" + SessionCookie + "\tvalid\n",
+			wantUser: "foo",
+		},
+		{
+			name:    "cookie of another site",
+			cookies: DOMAIN + "\tFALSE\t/\tTRUE\t0\t" + SessionCookie + "\tvalid\n",
+			wantErr: "parse",
+		},
+		{
+			name:    "invalid session",
+			cookies: host + "\tFALSE\t/\tFALSE\t0\t" + SessionCookie + "\texpired\n",
+			wantErr: "import",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session, err := LoadSession(filepath.Join(t.TempDir(), "session.json"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			client := NewClient(nil, WithBaseURL(base), WithSession(session))
+			defer client.Shutdown()
+
+			cookies, err := client.ParseCookies(strings.NewReader(tt.cookies))
+			if (err != nil) != (tt.wantErr == "parse") {
+				t.Fatalf("ParseCookies: err = %v, want error %t", err, tt.wantErr == "parse")
+			}
+			if err != nil {
+				return
+			}
+			user, err := client.ImportSession(context.Background(), cookies)
+			if (err != nil) != (tt.wantErr == "import") {
+				t.Fatalf("ImportSession: err = %v, want error %t", err, tt.wantErr == "import")
+			}
+			if user != tt.wantUser {
+				t.Errorf("user = %q, want %q", user, tt.wantUser)
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cry999/atcoder-cli/contests"
+	"golang.org/x/net/html"
+)
+
+// fixtimeLayout is the layout of the times shown in the contest pages.
+const fixtimeLayout = "2006-01-02 15:04:05-0700"
+
+var penaltyPattern = regexp.MustCompile(`(\d+)\s*(分|秒|minutes?|seconds?)`)
+
+// FetchContest fetches the metadata of the contest from its top page.
+func (c *Client) FetchContest(ctx context.Context) (*contests.Contest, error) {
+	contestURL, err := c.baseURL.Parse(path.Join("contests", c.family.ContestName()))
+	if err != nil {
+		return nil, err
+	}
+
+	slog.InfoContext(ctx, "fetching contest", slog.String("url", contestURL.String()))
+
+	req, err := http.NewRequestWithContext(ctx, "GET", contestURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.doCached(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	root, err := html.Parse(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	contest := &contests.Contest{ID: c.family.ContestName()}
+
+	if title, err := findOneNode(root, func(n *html.Node) bool {
+		return n.Type == html.ElementNode && n.Data == "a" && hasClass(n, "contest-title")
+	}); err == nil {
+		contest.Title = strings.TrimSpace(textContent(title))
+	}
+
+	// small.contest-duration > a > time が開始と終了の 2 つある
+	if duration, err := findOneNode(root, func(n *html.Node) bool {
+		return n.Type == html.ElementNode && hasClass(n, "contest-duration")
+	}); err == nil {
+		times := findAllNodes(duration, func(n *html.Node) bool {
+			return n.Type == html.ElementNode && n.Data == "time"
+		})
+		if len(times) >= 2 {
+			startAt, err := time.Parse(fixtimeLayout, strings.TrimSpace(textContent(times[0])))
+			if err != nil {
+				return nil, fmt.Errorf("invalid start time: %w", err)
+			}
+			endAt, err := time.Parse(fixtimeLayout, strings.TrimSpace(textContent(times[1])))
+			if err != nil {
+				return nil, fmt.Errorf("invalid end time: %w", err)
+			}
+			contest.StartAt = startAt
+			contest.Duration = endAt.Sub(startAt)
+		}
+	}
+
+	for key, value := range contestInfo(root) {
+		switch key {
+		case "Rated Range", "Rated対象":
+			contest.RatedRange = value
+		case "Penalty", "ペナルティ":
+			contest.Penalty = parsePenalty(value)
+		}
+	}
+
+	return contest, nil
+}
+
+// contestInfoKeys are the keys of the info block of a contest page.
+var contestInfoKeys = map[string]bool{
+	"Can Participate": true, "参加対象": true,
+	"Rated Range": true, "Rated対象": true,
+	"Penalty": true, "ペナルティ": true,
+}
+
+// contestInfo returns the items of the info block of the contest page, such
+// as "Rated Range: - 1999" and "ペナルティ: 5 分", keyed by their names. The
+// block is the element with the most of those items as its child spans, so
+// that other spans of the page are not taken for them.
+func contestInfo(root *html.Node) map[string]string {
+	item := func(n *html.Node) (string, string, bool) {
+		if n.Type != html.ElementNode || n.Data != "span" {
+			return "", "", false
+		}
+		key, value, ok := strings.Cut(textContent(n), ":")
+		key = strings.TrimSpace(key)
+		return key, strings.TrimSpace(value), ok && contestInfoKeys[key]
+	}
+
+	count := func(n *html.Node) int {
+		items := 0
+		for c := range n.ChildNodes() {
+			if _, _, ok := item(c); ok {
+				items++
+			}
+		}
+		return items
+	}
+
+	// 項目が一番多く並んでいる要素を情報欄とみなす
+	var block *html.Node
+	for _, n := range findAllNodes(root, func(n *html.Node) bool { return count(n) > 0 }) {
+		if block == nil || count(n) > count(block) {
+			block = n
+		}
+	}
+	info := map[string]string{}
+	if block == nil {
+		return info
+	}
+	for c := range block.ChildNodes() {
+		if key, value, ok := item(c); ok {
+			info[key] = value
+		}
+	}
+	return info
+}
+
+// parsePenalty parses a penalty such as "5 minutes" or "5 分". Zero is
+// returned for "None" or "なし".
+func parsePenalty(s string) time.Duration {
+	m := penaltyPattern.FindStringSubmatch(s)
+	if m == nil {
+		return 0
+	}
+	n, _ := strconv.Atoi(m[1])
+	if m[2] == "秒" || strings.HasPrefix(m[2], "second") {
+		return time.Duration(n) * time.Second
+	}
+	return time.Duration(n) * time.Minute
+}
@@ -0,0 +1,134 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cry999/atcoder-cli/contests"
+	"golang.org/x/net/html"
+)
+
+// ContestListSection is a section of the contest list page.
+type ContestListSection string
+
+// Possible values for ContestListSection, named after the id of the section.
+const (
+	ContestsRunning  ContestListSection = "contest-table-action"
+	ContestsUpcoming ContestListSection = "contest-table-upcoming"
+	ContestsRecent   ContestListSection = "contest-table-recent"
+)
+
+// FetchContestList fetches the contests listed in the sections of the
+// contest list page. A section missing from the page (e.g. no running
+// contests) yields no contests.
+func (c *Client) FetchContestList(ctx context.Context, sections ...ContestListSection) (map[ContestListSection][]*contests.Contest, error) {
+	listURL, err := c.baseURL.Parse("contests/")
+	if err != nil {
+		return nil, err
+	}
+
+	slog.InfoContext(ctx, "fetching contest list", slog.String("url", listURL.String()))
+
+	req, err := http.NewRequestWithContext(ctx, "GET", listURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	root, err := html.Parse(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	result := map[ContestListSection][]*contests.Contest{}
+	for _, section := range sections {
+		div, err := findOneNode(root, func(n *html.Node) bool {
+			return n.Type == html.ElementNode && attrIs(n, "id", string(section))
+		})
+		if err != nil {
+			continue
+		}
+		for _, tr := range findAllNodes(div, func(n *html.Node) bool {
+			return n.Type == html.ElementNode && n.Data == "tr" && n.Parent != nil && n.Parent.Data == "tbody"
+		}) {
+			contest, err := parseContestRow(tr)
+			if err != nil {
+				slog.WarnContext(ctx, "skipped contest row", slog.String("section", string(section)), slog.String("err", err.Error()))
+				continue
+			}
+			result[section] = append(result[section], contest)
+		}
+	}
+	return result, nil
+}
+
+// parseContestRow parses a row of the contest list:
+// start time, contest name, duration and rated range.
+func parseContestRow(tr *html.Node) (*contests.Contest, error) {
+	var tds []*html.Node
+	for n := range tr.ChildNodes() {
+		if n.Type == html.ElementNode && n.Data == "td" {
+			tds = append(tds, n)
+		}
+	}
+	if len(tds) < 4 {
+		return nil, fmt.Errorf("unexpected number of columns: %d", len(tds))
+	}
+
+	startAt, err := time.Parse(fixtimeLayout, strings.TrimSpace(textContent(tds[0])))
+	if err != nil {
+		return nil, fmt.Errorf("invalid start time: %w", err)
+	}
+
+	a, err := findOneNode(tds[1], func(n *html.Node) bool {
+		if n.Type != html.ElementNode || n.Data != "a" {
+			return false
+		}
+		href, ok := getAttr(n, "href")
+		return ok && strings.HasPrefix(href, "/contests/")
+	})
+	if err != nil {
+		return nil, fmt.Errorf("no contest link: %w", err)
+	}
+	href, _ := getAttr(a, "href")
+
+	duration, err := parseHourMinute(strings.TrimSpace(textContent(tds[2])))
+	if err != nil {
+		return nil, err
+	}
+
+	return &contests.Contest{
+		ID:         path.Base(href),
+		Title:      strings.TrimSpace(textContent(a)),
+		StartAt:    startAt,
+		Duration:   duration,
+		RatedRange: strings.TrimSpace(textContent(tds[3])),
+	}, nil
+}
+
+// parseHourMinute parses a duration such as "01:40" or "240:00".
+func parseHourMinute(s string) (time.Duration, error) {
+	rawHour, rawMinute, ok := strings.Cut(s, ":")
+	if !ok {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+	hour, err := strconv.Atoi(rawHour)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q: %w", s, err)
+	}
+	minute, err := strconv.Atoi(rawMinute)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q: %w", s, err)
+	}
+	return time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute, nil
+}
@@ -0,0 +1,159 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SessionCookie is the name of the cookie holding the AtCoder session.
+const SessionCookie = "REVEL_SESSION"
+
+// ParseCookies parses cookies exported from a browser. Both the Netscape
+// cookie file format and a bare REVEL_SESSION value (optionally given as
+// "REVEL_SESSION=...") are accepted. Only the cookies for the site of the
+// client, AtCoder unless changed by WithBaseURL, are returned.
+func (c *Client) ParseCookies(r io.Reader) ([]*http.Cookie, error) {
+	var (
+		cookies []*http.Cookie
+		lines   []string
+	)
+	host := c.baseURL.Hostname()
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		lines = append(lines, line)
+
+		httpOnly := false
+		if after, ok := strings.CutPrefix(line, "#HttpOnly_"); ok {
+			line, httpOnly = after, true
+		} else if strings.HasPrefix(line, "#") {
+			continue
+		}
+		// domain, include subdomains, path, secure, expiry, name, value
+		fields := strings.Split(line, "\t")
+		if len(fields) != 7 {
+			continue
+		}
+		domain := strings.TrimPrefix(fields[0], ".")
+		if domain != host && !strings.HasSuffix(domain, "."+host) {
+			continue
+		}
+		cookie := &http.Cookie{
+			Name:     fields[5],
+			Value:    fields[6],
+			Path:     fields[2],
+			Secure:   strings.EqualFold(fields[3], "TRUE"),
+			HttpOnly: httpOnly,
+		}
+		if strings.EqualFold(fields[1], "TRUE") {
+			cookie.Domain = domain
+		}
+		if expiry, err := strconv.ParseInt(fields[4], 10, 64); err == nil && expiry > 0 {
+			cookie.Expires = time.Unix(expiry, 0)
+		}
+		cookies = append(cookies, cookie)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(cookies) > 0 {
+		return cookies, nil
+	}
+
+	// Netscape 形式でなければ REVEL_SESSION の値そのものとみなす
+	if len(lines) == 1 && !strings.HasPrefix(lines[0], "#") && !strings.Contains(lines[0], "\t") {
+		value := strings.TrimPrefix(lines[0], SessionCookie+"=")
+		return []*http.Cookie{{Name: SessionCookie, Value: value, Path: "/", Secure: true, HttpOnly: true}}, nil
+	}
+	return nil, fmt.Errorf("no cookies for %s found", host)
+}
+
+// sessionField returns the field of the data of the session cookie. The
+// value is "<signature>-<url encoded data>", where the data is "key:value"
+// fields separated by NUL.
+func sessionField(cookie *http.Cookie, key string) (string, bool) {
+	_, data, ok := strings.Cut(cookie.Value, "-")
+	if !ok {
+		return "", false
+	}
+	data, err := url.QueryUnescape(data)
+	if err != nil {
+		return "", false
+	}
+	for field := range strings.SplitSeq(data, "\x00") {
+		if value, ok := strings.CutPrefix(field, key+":"); ok {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+// sessionExpiry returns the expiry of the session cookie. A session cookie
+// without Expires still carries its expiry as "_TS:<unix time>" in the data.
+func sessionExpiry(cookie *http.Cookie) (time.Time, bool) {
+	if !cookie.Expires.IsZero() {
+		return cookie.Expires, true
+	}
+	ts, ok := sessionField(cookie, "_TS")
+	if !ok {
+		return time.Time{}, false
+	}
+	unix, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(unix, 0), true
+}
+
+// ExpiresAt returns when the logged-in session expires, if known.
+func (s *Session) ExpiresAt() (time.Time, bool) {
+	cookie, ok := s.Cookie(SessionCookie)
+	if !ok {
+		return time.Time{}, false
+	}
+	return sessionExpiry(cookie)
+}
+
+// User returns the name of the logged-in user recorded in the session cookie,
+// without asking the server. Use Client.Whoami to verify the session.
+func (s *Session) User() (string, bool) {
+	cookie, ok := s.Cookie(SessionCookie)
+	if !ok {
+		return "", false
+	}
+	user, ok := sessionField(cookie, "UserScreenName")
+	return user, ok && user != ""
+}
+
+// ImportSession stores the cookies in the session of the client and returns
+// the name of the logged-in user. The session is restored as it was if the
+// cookies do not make a logged-in session.
+func (c *Client) ImportSession(ctx context.Context, cookies []*http.Cookie) (string, error) {
+	session, ok := c.httpClient.Jar.(*Session)
+	if !ok {
+		return "", errors.New("client has no session")
+	}
+	previous := session.snapshot()
+	session.SetCookies(c.baseURL, cookies)
+
+	user, err := c.Whoami(ctx)
+	if err != nil {
+		if restoreErr := session.restore(previous); restoreErr != nil {
+			return "", errors.Join(err, restoreErr)
+		}
+		return "", fmt.Errorf("imported cookies are not a valid session: %w", err)
+	}
+	return user, nil
+}
@@ -3,6 +3,7 @@ package api
 import (
 	"fmt"
 	"slices"
+	"strings"
 
 	"golang.org/x/net/html"
 )
@@ -54,3 +55,23 @@ func getAttr(n *html.Node, k string) (string, bool) {
 	}
 	return "", false
 }
+
+func hasClass(n *html.Node, class string) bool {
+	classes, ok := getAttr(n, "class")
+	if !ok {
+		return false
+	}
+	return slices.Contains(strings.Fields(classes), class)
+}
+
+// textContent returns the concatenated text of the node and its descendants.
+func textContent(n *html.Node) string {
+	if n.Type == html.TextNode {
+		return n.Data
+	}
+	var sb strings.Builder
+	for c := range n.ChildNodes() {
+		sb.WriteString(textContent(c))
+	}
+	return sb.String()
+}
@@ -0,0 +1,104 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"path"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// LanguagesContest is the contest whose submit page lists the languages when
+// the client is not bound to a contest.
+const LanguagesContest = "practice"
+
+// Language is a language accepted by the judge.
+type Language struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// String returns the language as shown in the submit page, e.g.
+// "Python (CPython 3.11.4)".
+func (l Language) String() string {
+	if l.Version == "" {
+		return l.Name
+	}
+	return l.Name + " (" + l.Version + ")"
+}
+
+// Languages is the list of languages in the order of the submit page.
+type Languages []Language
+
+// Lookup returns the language with the ID.
+func (ls Languages) Lookup(id string) (Language, bool) {
+	for _, l := range ls {
+		if l.ID == id {
+			return l, true
+		}
+	}
+	return Language{}, false
+}
+
+// Match returns the first language whose name contains the pattern, ignoring
+// case (e.g. "PyPy 3" matches "Python (PyPy 3.10-v7.3.12)").
+func (ls Languages) Match(pattern string) (Language, bool) {
+	pattern = strings.ToLower(pattern)
+	for _, l := range ls {
+		if strings.Contains(strings.ToLower(l.String()), pattern) {
+			return l, true
+		}
+	}
+	return Language{}, false
+}
+
+// FetchLanguages fetches the languages from the language select of the submit
+// page. The submit page needs a logged-in session.
+func (c *Client) FetchLanguages(ctx context.Context) (Languages, error) {
+	contest := LanguagesContest
+	if c.family != nil {
+		contest = c.family.ContestName()
+	}
+	submitURL, err := c.baseURL.Parse(path.Join("contests", contest, "submit"))
+	if err != nil {
+		return nil, err
+	}
+
+	slog.InfoContext(ctx, "fetching languages", slog.String("url", submitURL.String()))
+
+	root, err := c.fetchPage(ctx, submitURL)
+	if err != nil {
+		return nil, err
+	}
+
+	// 問題ごとに言語の select があるので ID で重複を除く
+	var languages Languages
+	seen := map[string]bool{}
+	for _, option := range findAllNodes(root, func(n *html.Node) bool {
+		return n.Type == html.ElementNode && n.Data == "option" &&
+			n.Parent != nil && n.Parent.Data == "select" && attrIs(n.Parent, "name", "data.LanguageId")
+	}) {
+		id, _ := getAttr(option, "value")
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		languages = append(languages, parseLanguage(id, strings.TrimSpace(textContent(option))))
+	}
+	if len(languages) == 0 {
+		return nil, fmt.Errorf("no languages found in %s", submitURL)
+	}
+	return languages, nil
+}
+
+// parseLanguage splits the label of a language option, e.g.
+// "C++ 20 (gcc 12.2)", into the name and the version.
+func parseLanguage(id, label string) Language {
+	if i := strings.LastIndex(label, " ("); i >= 0 && strings.HasSuffix(label, ")") {
+		return Language{ID: id, Name: label[:i], Version: label[i+2 : len(label)-1]}
+	}
+	return Language{ID: id, Name: label}
+}
@@ -0,0 +1,73 @@
+package api
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Default rate limit of the requests.
+const (
+	DefaultRate  = 10.0
+	DefaultBurst = 4
+)
+
+// limiter is a token bucket: it holds up to burst tokens, refilled at rate
+// tokens per second, and each request takes one.
+type limiter struct {
+	rate  float64
+	burst int
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+func newLimiter(rate float64, burst int) *limiter {
+	burst = max(burst, 1)
+	return &limiter{rate: rate, burst: burst, tokens: float64(burst), last: time.Now()}
+}
+
+// reserve takes a token and returns how long to wait until it is available.
+// The tokens go negative while waiting, so that the waiters are served in
+// order.
+func (l *limiter) reserve() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	l.tokens = min(l.tokens+now.Sub(l.last).Seconds()*l.rate, float64(l.burst))
+	l.last = now
+	l.tokens--
+	if l.tokens >= 0 || l.rate <= 0 {
+		return 0
+	}
+	return time.Duration(-l.tokens / l.rate * float64(time.Second))
+}
+
+// cancel gives back a token taken but not used.
+func (l *limiter) cancel() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.tokens = min(l.tokens+1, float64(l.burst))
+}
+
+// wait blocks until a token is available.
+func (l *limiter) wait(ctx context.Context, done <-chan struct{}) error {
+	d := l.reserve()
+	if d == 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		l.cancel()
+		return ctx.Err()
+	case <-done:
+		l.cancel()
+		return errShutdown
+	}
+}
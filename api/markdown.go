@@ -0,0 +1,271 @@
+package api
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// Statement is the statement of a task converted into Markdown.
+type Statement struct {
+	Markdown string
+	// Images are the images referred to by the Markdown with local paths.
+	Images []StatementImage
+}
+
+// StatementImage is an image of a statement to be downloaded to Path, which
+// is relative to the Markdown file.
+type StatementImage struct {
+	Path string
+	URL  *url.URL
+}
+
+var spacePattern = regexp.MustCompile(`\s+`)
+
+// blockElements are rendered as blocks separated by blank lines.
+var blockElements = map[string]bool{
+	"p": true, "div": true, "section": true, "pre": true, "ul": true, "ol": true,
+	"table": true, "hr": true, "blockquote": true, "details": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+}
+
+// markdown converts a statement into Markdown. <var> and KaTeX become
+// "$...$" math, and images are renamed to local paths.
+type markdown struct {
+	base   *url.URL
+	images []StatementImage
+	paths  map[string]string
+}
+
+// convertStatement converts the statement into Markdown with the title as the
+// heading. Relative links and images are resolved against base.
+func convertStatement(statement *html.Node, title string, base *url.URL) *Statement {
+	m := &markdown{base: base, paths: map[string]string{}}
+	blocks := m.blocks(statement)
+	if title != "" {
+		blocks = append([]string{"# " + title}, blocks...)
+	}
+	return &Statement{Markdown: strings.Join(blocks, "\n\n") + "\n", Images: m.images}
+}
+
+// isBlock reports whether the node is rendered as a block: a block element,
+// or an inline one containing blocks such as the language <span>.
+func isBlock(n *html.Node) bool {
+	if n.Type != html.ElementNode {
+		return false
+	}
+	if blockElements[n.Data] {
+		return true
+	}
+	_, err := findOneNode(n, func(d *html.Node) bool {
+		return d != n && d.Type == html.ElementNode && blockElements[d.Data]
+	})
+	return err == nil
+}
+
+// blocks renders the children of the node as blocks. Inline children between
+// block ones are put together into paragraphs.
+func (m *markdown) blocks(n *html.Node) []string {
+	var (
+		out []string
+		run strings.Builder
+	)
+	flush := func() {
+		if text := strings.TrimSpace(run.String()); text != "" {
+			out = append(out, text)
+		}
+		run.Reset()
+	}
+	for c := range n.ChildNodes() {
+		if !isBlock(c) {
+			run.WriteString(m.inline(c))
+			continue
+		}
+		flush()
+		if b := m.block(c); b != "" {
+			out = append(out, b)
+		}
+	}
+	flush()
+	return out
+}
+
+func (m *markdown) block(n *html.Node) string {
+	switch n.Data {
+	case "h1", "h2", "h3", "h4", "h5", "h6":
+		// タイトルを # にするので見出しは ## から
+		level := max(int(n.Data[1]-'0')-1, 2)
+		return strings.Repeat("#", level) + " " + strings.TrimSpace(m.inlineChildren(n))
+	case "p":
+		return strings.TrimSpace(m.inlineChildren(n))
+	case "pre":
+		return "```\n" + strings.TrimRight(textContent(n), "\n") + "\n```"
+	case "ul", "ol":
+		return m.list(n, n.Data == "ol")
+	case "table":
+		return m.table(n)
+	case "hr":
+		return "---"
+	case "blockquote":
+		lines := strings.Split(strings.Join(m.blocks(n), "\n\n"), "\n")
+		for i, line := range lines {
+			lines[i] = strings.TrimRight("> "+line, " ")
+		}
+		return strings.Join(lines, "\n")
+	default:
+		return strings.Join(m.blocks(n), "\n\n")
+	}
+}
+
+func (m *markdown) list(n *html.Node, ordered bool) string {
+	var items []string
+	for li := range n.ChildNodes() {
+		if li.Type != html.ElementNode || li.Data != "li" {
+			continue
+		}
+		marker := "- "
+		if ordered {
+			marker = fmt.Sprintf("%d. ", len(items)+1)
+		}
+		// 入れ子のリストなどの続きの行はマーカーの幅だけ字下げする
+		indent := strings.Repeat(" ", len(marker))
+		lines := strings.Split(strings.Join(m.blocks(li), "\n\n"), "\n")
+		for i := 1; i < len(lines); i++ {
+			if lines[i] != "" {
+				lines[i] = indent + lines[i]
+			}
+		}
+		items = append(items, marker+strings.Join(lines, "\n"))
+	}
+	return strings.Join(items, "\n")
+}
+
+func (m *markdown) table(n *html.Node) string {
+	var rows [][]string
+	columns := 0
+	for _, tr := range findAllNodes(n, func(d *html.Node) bool {
+		return d.Type == html.ElementNode && d.Data == "tr"
+	}) {
+		var row []string
+		for cell := range tr.ChildNodes() {
+			if cell.Type != html.ElementNode || (cell.Data != "th" && cell.Data != "td") {
+				continue
+			}
+			text := strings.TrimSpace(spacePattern.ReplaceAllString(m.inlineChildren(cell), " "))
+			row = append(row, strings.ReplaceAll(text, "|", `\|`))
+		}
+		columns = max(columns, len(row))
+		rows = append(rows, row)
+	}
+	if len(rows) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	writeRow := func(row []string) {
+		sb.WriteString("|")
+		for i := range columns {
+			cell := ""
+			if i < len(row) {
+				cell = row[i]
+			}
+			sb.WriteString(" " + cell + " |")
+		}
+		sb.WriteString("\n")
+	}
+	// 1 行目を見出しとして区切りを入れる
+	writeRow(rows[0])
+	sb.WriteString("|" + strings.Repeat(" --- |", columns) + "\n")
+	for _, row := range rows[1:] {
+		writeRow(row)
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+func (m *markdown) inlineChildren(n *html.Node) string {
+	var sb strings.Builder
+	for c := range n.ChildNodes() {
+		sb.WriteString(m.inline(c))
+	}
+	return sb.String()
+}
+
+func (m *markdown) inline(n *html.Node) string {
+	switch n.Type {
+	case html.TextNode:
+		return spacePattern.ReplaceAllString(n.Data, " ")
+	case html.ElementNode:
+	default:
+		return ""
+	}
+
+	// 見出しなどにあるコピーボタンは本文ではない
+	if hasClass(n, "btn") {
+		return ""
+	}
+
+	switch n.Data {
+	case "var":
+		return "$" + strings.TrimSpace(textContent(n)) + "$"
+	case "br":
+		return "\n"
+	case "strong", "b":
+		return "**" + strings.TrimSpace(m.inlineChildren(n)) + "**"
+	case "em", "i":
+		return "*" + strings.TrimSpace(m.inlineChildren(n)) + "*"
+	case "code", "tt", "kbd", "samp":
+		return "`" + textContent(n) + "`"
+	case "a":
+		text := strings.TrimSpace(m.inlineChildren(n))
+		href, ok := getAttr(n, "href")
+		if !ok {
+			return text
+		}
+		if u, err := m.base.Parse(href); err == nil {
+			href = u.String()
+		}
+		return "[" + text + "](" + href + ")"
+	case "img":
+		return m.image(n)
+	case "script", "style", "button":
+		return ""
+	}
+
+	// サーバーで描画済みの KaTeX は元の TeX を使う
+	if hasClass(n, "katex") {
+		if annotation, err := findOneNode(n, func(d *html.Node) bool {
+			return d.Type == html.ElementNode && d.Data == "annotation" && attrIs(d, "encoding", "application/x-tex")
+		}); err == nil {
+			return "$" + strings.TrimSpace(textContent(annotation)) + "$"
+		}
+	}
+	return m.inlineChildren(n)
+}
+
+// image renders the image with a local path, and records it to download.
+func (m *markdown) image(n *html.Node) string {
+	alt, _ := getAttr(n, "alt")
+	src, ok := getAttr(n, "src")
+	if !ok {
+		return ""
+	}
+	u, err := m.base.Parse(src)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return "![" + alt + "](" + src + ")"
+	}
+	local, ok := m.paths[u.String()]
+	if !ok {
+		name := path.Base(u.Path)
+		if name == "/" || name == "." {
+			name = "image"
+		}
+		local = fmt.Sprintf("images/%02d-%s", len(m.images)+1, name)
+		m.paths[u.String()] = local
+		m.images = append(m.images, StatementImage{Path: local, URL: u})
+	}
+	return "![" + alt + "](" + local + ")"
+}
@@ -0,0 +1,97 @@
+package api
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/net/html"
+)
+
+// statementPage is a task page with both languages, including the copy
+// buttons in the sample headings.
+const statementPage = `<div id="task-statement"><span class="lang"><span class="lang-ja">
+<p>配点 : <var>100</var> 点</p>
+<div class="part"><section><h3>問題文</h3><p><var>N</var> 個の整数 <var>A_i</var> があります。<a href="/contests/abc350/tasks/abc350_a">A 問題</a>を参照。</p>
+<p><img src="/img/other/abc350_d/fig.png" alt="図"></p></section></div>
+<div class="part"><section><h3>制約</h3><ul><li><var>1 \leq N \leq 10^5</var></li><li>入力は全て整数<ul><li>入れ子</li></ul></li></ul></section></div>
+<div class="part"><section><h3>表</h3><table><thead><tr><th>i</th><th>A_i</th></tr></thead><tbody><tr><td>1</td><td>a|b</td></tr></tbody></table></section></div>
+<div class="part"><section><h3>入力例 1<span class="btn btn-default btn-sm btn-copy">Copy</span></h3><pre>3
+1 2 3
+</pre></section></div>
+</span><span class="lang-en">
+<div class="part"><section><h3>Problem Statement</h3><p>There are <var>N</var> integers.</p><ol><li>first</li><li>second</li></ol></section></div>
+<div class="part"><section><h3>Sample Output 1<span class="btn btn-default btn-sm btn-copy">Copy</span></h3><pre>6
+</pre></section></div>
+</span></span></div>`
+
+func TestConvertStatement(t *testing.T) {
+	base, err := url.Parse("https://atcoder.jp/contests/abc350/tasks/abc350_d")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		lang       string
+		want       string
+		wantImages []string
+	}{
+		{
+			name: "japanese",
+			lang: "ja",
+			want: "# D - Title\n\n" +
+				"配点 : $100$ 点\n\n" +
+				"## 問題文\n\n" +
+				"$N$ 個の整数 $A_i$ があります。[A 問題](https://atcoder.jp/contests/abc350/tasks/abc350_a)を参照。\n\n" +
+				"![図](images/01-fig.png)\n\n" +
+				"## 制約\n\n" +
+				"- $1 \\leq N \\leq 10^5$\n" +
+				"- 入力は全て整数\n\n" +
+				"  - 入れ子\n\n" +
+				"## 表\n\n" +
+				"| i | A_i |\n" +
+				"| --- | --- |\n" +
+				"| 1 | a\\|b |\n\n" +
+				"## 入力例 1\n\n" +
+				"```\n3\n1 2 3\n```\n",
+			wantImages: []string{"images/01-fig.png https://atcoder.jp/img/other/abc350_d/fig.png"},
+		},
+		{
+			name: "english",
+			lang: "en",
+			want: "# D - Title\n\n" +
+				"## Problem Statement\n\n" +
+				"There are $N$ integers.\n\n" +
+				"1. first\n" +
+				"2. second\n\n" +
+				"## Sample Output 1\n\n" +
+				"```\n6\n```\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root, err := html.Parse(strings.NewReader(statementPage))
+			if err != nil {
+				t.Fatal(err)
+			}
+			statement, err := taskStatement(root, tt.lang)
+			if err != nil {
+				t.Fatalf("taskStatement: %v", err)
+			}
+
+			got := convertStatement(statement, "D - Title", base)
+			if diff := cmp.Diff(tt.want, got.Markdown); diff != "" {
+				t.Errorf("Markdown mismatch (-want +got):\n%s", diff)
+			}
+			var images []string
+			for _, image := range got.Images {
+				images = append(images, image.Path+" "+image.URL.String())
+			}
+			if diff := cmp.Diff(tt.wantImages, images); diff != "" {
+				t.Errorf("images mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
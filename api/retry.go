@@ -0,0 +1,144 @@
+package api
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// DefaultRetryBudget is the default time spent waiting to retry a request.
+const DefaultRetryBudget = 30 * time.Second
+
+// Backoff between retries, doubled on each attempt.
+const (
+	retryInitialBackoff = 500 * time.Millisecond
+	retryMaxBackoff     = 10 * time.Second
+)
+
+// StatusError is returned for a response with a non-2xx status.
+type StatusError struct {
+	URL        string
+	StatusCode int
+	Status     string
+	// RetryAfter is the wait given by the Retry-After header. Zero if none.
+	RetryAfter time.Duration
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s: %s", e.URL, e.Status)
+}
+
+func newStatusError(resp *http.Response) *StatusError {
+	return &StatusError{
+		URL:        resp.Request.URL.String(),
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+	}
+}
+
+// parseRetryAfter parses the Retry-After header, either in seconds or as an
+// HTTP date.
+func parseRetryAfter(v string) time.Duration {
+	if v == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(v); err == nil {
+		return max(time.Duration(seconds)*time.Second, 0)
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return max(time.Until(t), 0)
+	}
+	return 0
+}
+
+// retryable reports whether the request may be sent again after the status,
+// or after a network error if status is 0. Only 429 guarantees that the
+// request was not processed, so other failures are retried only for
+// idempotent requests, not e.g. for a submission.
+func retryable(req *http.Request, status int) bool {
+	if status == http.StatusTooManyRequests {
+		return true
+	}
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		return false
+	}
+	switch status {
+	case 0, http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// backoff returns the jittered wait before the retry after the attempt.
+func backoff(attempt int) time.Duration {
+	d := retryMaxBackoff
+	if attempt < 16 {
+		d = min(retryInitialBackoff<<attempt, retryMaxBackoff)
+	}
+	return d/2 + rand.N(d/2+1)
+}
+
+// send sends the request, retrying on transient failures until the retry
+// budget is spent. A non-2xx response is returned as *StatusError.
+func (c *Client) send(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	deadline := time.Now().Add(c.retryBudget)
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+
+		if err := c.limiter.wait(ctx, c.shutdownCh); err != nil {
+			return nil, err
+		}
+
+		var wait time.Duration
+		resp, err := c.httpClient.Do(req)
+		switch {
+		case err != nil:
+			if ctx.Err() != nil || !retryable(req, 0) {
+				return nil, err
+			}
+		case resp.StatusCode >= 200 && resp.StatusCode < 300:
+			return resp, nil
+		default:
+			statusErr := newStatusError(resp)
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+			if !retryable(req, resp.StatusCode) {
+				return nil, statusErr
+			}
+			err, wait = statusErr, statusErr.RetryAfter
+		}
+
+		if wait == 0 {
+			wait = backoff(attempt)
+		}
+		if time.Now().Add(wait).After(deadline) {
+			return nil, err
+		}
+		slog.WarnContext(
+			ctx, "retrying request",
+			slog.String("url", req.URL.String()),
+			slog.Int("attempt", attempt+1),
+			slog.Duration("wait", wait),
+			slog.String("err", err.Error()),
+		)
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-c.shutdownCh:
+			return nil, errShutdown
+		}
+	}
+}
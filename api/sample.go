@@ -0,0 +1,150 @@
+package api
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// sampleHeadingPattern matches the heading of a sample section, e.g.
+// "入力例 1" or "Sample Output 2". The number is missing in some old tasks
+// with a single sample.
+var sampleHeadingPattern = regexp.MustCompile(`^(入力例|出力例|Sample Input|Sample Output)\s*(\d*)$`)
+
+// SampleError reports a sample whose input or output is missing.
+type SampleError struct {
+	Number int
+	// Missing is "input" or "output".
+	Missing string
+}
+
+func (e *SampleError) Error() string {
+	return fmt.Sprintf("sample %d has no %s", e.Number, e.Missing)
+}
+
+// taskStatement returns the statement of the task page in the language ("ja"
+// or "en"). It falls back to the other language, and to the whole statement
+// for old tasks without language sections.
+func taskStatement(root *html.Node, lang string) (*html.Node, error) {
+	statement, err := findOneNode(root, func(n *html.Node) bool {
+		return n.Type == html.ElementNode && attrIs(n, "id", "task-statement")
+	})
+	if err != nil {
+		return nil, errors.New("no task statement")
+	}
+	for _, l := range []string{lang, "ja", "en"} {
+		if section, err := findOneNode(statement, func(n *html.Node) bool {
+			return n.Type == html.ElementNode && hasClass(n, "lang-"+l)
+		}); err == nil {
+			return section, nil
+		}
+	}
+	return statement, nil
+}
+
+// sampleHeading returns whether the section is a sample input or output, and
+// its number (0 if none). Only the text directly in the h3 is used, so that
+// e.g. a copy button in it is ignored.
+func sampleHeading(section *html.Node) (isInput bool, number int, ok bool) {
+	h3, err := findOneNode(section, func(n *html.Node) bool {
+		return n.Type == html.ElementNode && n.Data == "h3"
+	})
+	if err != nil {
+		return false, 0, false
+	}
+	var sb strings.Builder
+	for c := range h3.ChildNodes() {
+		if c.Type == html.TextNode {
+			sb.WriteString(c.Data)
+		}
+	}
+	// 全角数字の見出しもある
+	heading := strings.Map(func(r rune) rune {
+		if '０' <= r && r <= '９' {
+			return '0' + r - '０'
+		}
+		return r
+	}, strings.TrimSpace(sb.String()))
+
+	m := sampleHeadingPattern.FindStringSubmatch(heading)
+	if m == nil {
+		return false, 0, false
+	}
+	if m[2] != "" {
+		number, err = strconv.Atoi(m[2])
+		if err != nil {
+			return false, 0, false
+		}
+	}
+	return m[1] == "入力例" || m[1] == "Sample Input", number, true
+}
+
+// parseSampleIOs parses the sample sections of the statement in the order of
+// their numbers. The text of the <pre> is kept as it is, including the text
+// in nested markup such as <var>. A section without <pre> is an empty sample,
+// e.g. for a task printing nothing.
+func parseSampleIOs(statement *html.Node) ([]*TaskSampleIO, error) {
+	type sample struct {
+		input, output *string
+	}
+	samples := map[int]*sample{}
+	var inputs, outputs int
+	for _, section := range findAllNodes(statement, func(n *html.Node) bool {
+		return n.Type == html.ElementNode && n.Data == "section"
+	}) {
+		isInput, number, ok := sampleHeading(section)
+		if !ok {
+			continue
+		}
+		// 番号のない見出しは出現順に数える
+		if isInput {
+			inputs++
+			number = cmp.Or(number, inputs)
+		} else {
+			outputs++
+			number = cmp.Or(number, outputs)
+		}
+
+		var text string
+		if pre, err := findOneNode(section, func(n *html.Node) bool {
+			return n.Type == html.ElementNode && n.Data == "pre"
+		}); err == nil {
+			text = textContent(pre)
+		}
+
+		s, ok := samples[number]
+		if !ok {
+			s = &sample{}
+			samples[number] = s
+		}
+		if isInput {
+			s.input = &text
+		} else {
+			s.output = &text
+		}
+	}
+
+	var (
+		sampleIOs []*TaskSampleIO
+		errs      []error
+	)
+	for _, n := range slices.Sorted(maps.Keys(samples)) {
+		s := samples[n]
+		switch {
+		case s.input == nil:
+			errs = append(errs, &SampleError{Number: n, Missing: "input"})
+		case s.output == nil:
+			errs = append(errs, &SampleError{Number: n, Missing: "output"})
+		default:
+			sampleIOs = append(sampleIOs, &TaskSampleIO{Input: *s.input, Output: *s.output})
+		}
+	}
+	return sampleIOs, errors.Join(errs...)
+}
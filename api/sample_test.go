@@ -0,0 +1,133 @@
+package api
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/net/html"
+)
+
+// sampleSection returns a sample section as in the task pages, with the copy
+// button in the heading.
+func sampleSection(heading, pre string) string {
+	return `<div class="part"><section><h3>` + heading + `<span class="btn btn-default btn-sm">Copy</span></h3>` + pre + `</section></div>`
+}
+
+func TestParseSampleIOs(t *testing.T) {
+	bilingual := `<div id="task-statement"><span class="lang">` +
+		`<span class="lang-ja">` +
+		sampleSection("入力例 1", "<pre>1 2\n</pre>") +
+		sampleSection("出力例 1", "<pre>3\n</pre>") +
+		`</span><span class="lang-en">` +
+		sampleSection("Sample Input 1", "<pre>10 20\n</pre>") +
+		sampleSection("Sample Output 1", "<pre>30\n</pre>") +
+		`</span></span></div>`
+
+	tests := []struct {
+		name     string
+		page     string
+		lang     string
+		want     []*TaskSampleIO
+		wantErrs []SampleError
+	}{
+		{
+			name: "japanese section",
+			page: bilingual,
+			lang: "ja",
+			want: []*TaskSampleIO{{Input: "1 2\n", Output: "3\n"}},
+		},
+		{
+			name: "english section",
+			page: bilingual,
+			lang: "en",
+			want: []*TaskSampleIO{{Input: "10 20\n", Output: "30\n"}},
+		},
+		{
+			name: "empty output",
+			page: `<div id="task-statement">` +
+				sampleSection("入力例 1", "<pre>5\n</pre>") +
+				sampleSection("出力例 1", "<pre></pre>") +
+				`</div>`,
+			lang: "ja",
+			want: []*TaskSampleIO{{Input: "5\n", Output: ""}},
+		},
+		{
+			name: "var in pre",
+			page: `<div id="task-statement">` +
+				sampleSection("入力例 1", "<pre><var>N</var> <var>M</var>\n3 4</pre>") +
+				sampleSection("出力例 1", "<pre>7</pre>") +
+				`</div>`,
+			lang: "ja",
+			want: []*TaskSampleIO{{Input: "N M\n3 4", Output: "7"}},
+		},
+		{
+			name: "headings without numbers",
+			page: `<div id="task-statement">` +
+				sampleSection("Sample Input", "<pre>a\n</pre>") +
+				sampleSection("Sample Output", "<pre>b\n</pre>") +
+				`</div>`,
+			lang: "en",
+			want: []*TaskSampleIO{{Input: "a\n", Output: "b\n"}},
+		},
+		{
+			name: "full-width numbers",
+			page: `<div id="task-statement">` +
+				sampleSection("入力例 ２", "<pre>2\n</pre>") +
+				sampleSection("出力例 ２", "<pre>4\n</pre>") +
+				`</div>`,
+			lang: "ja",
+			want: []*TaskSampleIO{{Input: "2\n", Output: "4\n"}},
+		},
+		{
+			name: "mismatched pairs",
+			page: `<div id="task-statement">` +
+				sampleSection("入力例 1", "<pre>1\n</pre>") +
+				sampleSection("出力例 1", "<pre>2\n</pre>") +
+				sampleSection("入力例 2", "<pre>3\n</pre>") +
+				sampleSection("出力例 3", "<pre>6\n</pre>") +
+				`</div>`,
+			lang: "ja",
+			want: []*TaskSampleIO{{Input: "1\n", Output: "2\n"}},
+			wantErrs: []SampleError{
+				{Number: 2, Missing: "output"},
+				{Number: 3, Missing: "input"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root, err := html.Parse(strings.NewReader(tt.page))
+			if err != nil {
+				t.Fatal(err)
+			}
+			statement, err := taskStatement(root, tt.lang)
+			if err != nil {
+				t.Fatalf("taskStatement: %v", err)
+			}
+
+			got, err := parseSampleIOs(statement)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("samples mismatch (-want +got):\n%s", diff)
+			}
+			var gotErrs []SampleError
+			if err != nil {
+				joined, ok := err.(interface{ Unwrap() []error })
+				if !ok {
+					t.Fatalf("err = %v, want joined errors", err)
+				}
+				for _, e := range joined.Unwrap() {
+					var sampleErr *SampleError
+					if !errors.As(e, &sampleErr) {
+						t.Fatalf("err = %v, want *SampleError", e)
+					}
+					gotErrs = append(gotErrs, *sampleErr)
+				}
+			}
+			if diff := cmp.Diff(tt.wantErrs, gotErrs); diff != "" {
+				t.Errorf("errors mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
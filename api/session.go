@@ -0,0 +1,165 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Session is a cookie jar persisted to a file, so that a logged-in session
+// is reused across invocations.
+type Session struct {
+	path string
+	jar  *cookiejar.Jar
+
+	mu      sync.Mutex
+	cookies map[string]*storedCookie
+}
+
+// storedCookie is a cookie with the URL it was set for, which is needed to
+// restore it into the jar.
+type storedCookie struct {
+	URL    string       `json:"url"`
+	Cookie *http.Cookie `json:"cookie"`
+}
+
+// LoadSession loads the session saved at path. A missing file yields an
+// empty session which is saved to path once cookies are set.
+func LoadSession(path string) (*Session, error) {
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return nil, err
+	}
+	s := &Session{path: path, jar: jar, cookies: map[string]*storedCookie{}}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var stored []*storedCookie
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return nil, err
+	}
+	for _, sc := range stored {
+		u, err := url.Parse(sc.URL)
+		if err != nil {
+			return nil, err
+		}
+		if !sc.Cookie.Expires.IsZero() && sc.Cookie.Expires.Before(time.Now()) {
+			continue
+		}
+		s.jar.SetCookies(u, []*http.Cookie{sc.Cookie})
+		s.cookies[sc.Cookie.Name] = sc
+	}
+	return s, nil
+}
+
+// SetCookies implements http.CookieJar and saves the session.
+func (s *Session) SetCookies(u *url.URL, cookies []*http.Cookie) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.jar.SetCookies(u, cookies)
+	for _, c := range cookies {
+		if c.MaxAge < 0 || (!c.Expires.IsZero() && c.Expires.Before(time.Now())) {
+			delete(s.cookies, c.Name)
+			continue
+		}
+		if c.MaxAge > 0 {
+			c.Expires = time.Now().Add(time.Duration(c.MaxAge) * time.Second)
+			c.MaxAge = 0
+		}
+		s.cookies[c.Name] = &storedCookie{URL: u.String(), Cookie: c}
+	}
+	// the session is still usable in memory if it cannot be saved
+	_ = s.save()
+}
+
+// Cookies implements http.CookieJar.
+func (s *Session) Cookies(u *url.URL) []*http.Cookie {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.jar.Cookies(u)
+}
+
+// Cookie returns the stored cookie by the name.
+func (s *Session) Cookie(name string) (*http.Cookie, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sc, ok := s.cookies[name]
+	if !ok {
+		return nil, false
+	}
+	return sc.Cookie, true
+}
+
+// Clear removes all cookies and the saved file.
+func (s *Session) Clear() error {
+	return s.restore(nil)
+}
+
+// snapshot returns the stored cookies, to be put back by restore.
+func (s *Session) snapshot() []*storedCookie {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stored := make([]*storedCookie, 0, len(s.cookies))
+	for _, sc := range s.cookies {
+		stored = append(stored, sc)
+	}
+	return stored
+}
+
+// restore replaces the cookies with the stored ones and saves the session.
+// The saved file is removed if there are none.
+func (s *Session) restore(stored []*storedCookie) error {
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return err
+	}
+	cookies := map[string]*storedCookie{}
+	for _, sc := range stored {
+		u, err := url.Parse(sc.URL)
+		if err != nil {
+			return err
+		}
+		jar.SetCookies(u, []*http.Cookie{sc.Cookie})
+		cookies[sc.Cookie.Name] = sc
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.jar = jar
+	s.cookies = cookies
+	if len(cookies) > 0 {
+		return s.save()
+	}
+	if err := os.Remove(s.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (s *Session) save() error {
+	stored := make([]*storedCookie, 0, len(s.cookies))
+	for _, sc := range s.cookies {
+		stored = append(stored, sc)
+	}
+	data, err := json.MarshalIndent(stored, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0700); err != nil {
+		return err
+	}
+	// the session grants access to the account
+	return os.WriteFile(s.path, data, 0600)
+}
@@ -0,0 +1,179 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// progressPattern matches the status of a submission being judged, e.g.
+// "3/45 WJ" or "3/45 WA" (the first rejected case so far).
+var progressPattern = regexp.MustCompile(`^(\d+)\s*/\s*(\d+)\s*(\S*)$`)
+
+// SubmissionStatus is the judge status of a submission.
+type SubmissionStatus struct {
+	ID string
+	// Status is the verdict such as "AC" or "WA", or "WJ" while judging.
+	Status string
+	// Judged and Total are the progress of the judge while judging.
+	Judged, Total int
+	Score         string
+	ExecTime      string
+	Memory        string
+}
+
+// Final reports whether the judge has finished.
+func (s *SubmissionStatus) Final() bool {
+	return s.Total == 0 && s.Status != "WJ" && s.Status != "WR" && s.Status != "Judging"
+}
+
+// TestcaseResult is the result of a submission for a test case.
+type TestcaseResult struct {
+	Name     string
+	Status   string
+	ExecTime string
+	Memory   string
+}
+
+// SubmissionID returns the ID of the submission from its URL.
+func SubmissionID(submissionURL *url.URL) string {
+	return path.Base(submissionURL.Path)
+}
+
+func (c *Client) contestURL() (*url.URL, error) {
+	return c.baseURL.Parse(path.Join("contests", c.family.ContestName()) + "/")
+}
+
+// FetchSubmissionStatus fetches the judge status of the submission from the
+// status endpoint polled by the submissions page.
+func (c *Client) FetchSubmissionStatus(ctx context.Context, id string) (*SubmissionStatus, error) {
+	contestURL, err := c.contestURL()
+	if err != nil {
+		return nil, err
+	}
+	statusURL, err := contestURL.Parse("submissions/status/json")
+	if err != nil {
+		return nil, err
+	}
+	statusURL.RawQuery = url.Values{"sids[]": {id}}.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", statusURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		Result map[string]struct {
+			HTML  string `json:"Html"`
+			Score string `json:"Score"`
+		} `json:"Result"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("invalid submission status: %w", err)
+	}
+	result, ok := body.Result[id]
+	if !ok {
+		return nil, fmt.Errorf("no status for submission %s", id)
+	}
+
+	// Html は <td> の並びなので表の中に置いてから解析する
+	root, err := html.Parse(strings.NewReader("<table><tbody><tr>" + result.HTML + "</tr></tbody></table>"))
+	if err != nil {
+		return nil, err
+	}
+	tds := findAllNodes(root, func(n *html.Node) bool {
+		return n.Type == html.ElementNode && n.Data == "td"
+	})
+	if len(tds) == 0 {
+		return nil, fmt.Errorf("unexpected submission status: %s", result.HTML)
+	}
+
+	status := &SubmissionStatus{ID: id, Score: result.Score}
+	status.Status = strings.TrimSpace(textContent(tds[0]))
+	if m := progressPattern.FindStringSubmatch(status.Status); m != nil {
+		status.Judged, _ = strconv.Atoi(m[1])
+		status.Total, _ = strconv.Atoi(m[2])
+		status.Status = m[3]
+	}
+	if len(tds) >= 3 {
+		status.ExecTime = strings.TrimSpace(textContent(tds[1]))
+		status.Memory = strings.TrimSpace(textContent(tds[2]))
+	}
+	return status, nil
+}
+
+// FetchTestcaseResults fetches the per-testcase results from the submission
+// page. It returns no results if the page does not show them, e.g. for a
+// compile error.
+func (c *Client) FetchTestcaseResults(ctx context.Context, id string) ([]TestcaseResult, error) {
+	contestURL, err := c.contestURL()
+	if err != nil {
+		return nil, err
+	}
+	submissionURL, err := contestURL.Parse("submissions/" + id)
+	if err != nil {
+		return nil, err
+	}
+
+	slog.InfoContext(ctx, "fetching testcase results", slog.String("url", submissionURL.String()))
+
+	req, err := http.NewRequestWithContext(ctx, "GET", submissionURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	root, err := html.Parse(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	// テストケースごとの結果は Case Name / Status / Exec Time / Memory の表
+	var results []TestcaseResult
+	for _, table := range findAllNodes(root, func(n *html.Node) bool {
+		return n.Type == html.ElementNode && n.Data == "table"
+	}) {
+		th, err := findOneNode(table, func(n *html.Node) bool {
+			return n.Type == html.ElementNode && n.Data == "th"
+		})
+		if err != nil {
+			continue
+		}
+		if header := strings.TrimSpace(textContent(th)); header != "Case Name" && header != "ケース名" {
+			continue
+		}
+		for _, tr := range findAllNodes(table, func(n *html.Node) bool {
+			return n.Type == html.ElementNode && n.Data == "tr" && n.Parent != nil && n.Parent.Data == "tbody"
+		}) {
+			var cells []string
+			for td := range tr.ChildNodes() {
+				if td.Type == html.ElementNode && td.Data == "td" {
+					cells = append(cells, strings.TrimSpace(textContent(td)))
+				}
+			}
+			if len(cells) < 4 {
+				continue
+			}
+			results = append(results, TestcaseResult{Name: cells[0], Status: cells[1], ExecTime: cells[2], Memory: cells[3]})
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,212 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html"
+)
+
+// submissionPathPattern matches the path of a submission page.
+var submissionPathPattern = regexp.MustCompile(`/contests/[^/]+/submissions/(\d+)$`)
+
+// Submission is a row of the submissions page.
+type Submission struct {
+	ID          string    `json:"id"`
+	SubmittedAt time.Time `json:"submitted_at"`
+	// Task is the title of the task (e.g. "D - Popular Vote") and TaskID is
+	// its ID (e.g. "abc350_d").
+	Task       string `json:"task"`
+	TaskID     string `json:"task_id"`
+	User       string `json:"user"`
+	Language   string `json:"language"`
+	Score      int    `json:"score"`
+	CodeLength int    `json:"code_length"`
+	Status     string `json:"status"`
+	// ExecTimeMS and MemoryKB are zero if not judged, e.g. for a compile
+	// error.
+	ExecTimeMS int `json:"exec_time_ms"`
+	MemoryKB   int `json:"memory_kb"`
+}
+
+// SubmissionFilter filters the submissions on the server. Empty fields match
+// any submission.
+type SubmissionFilter struct {
+	TaskID string
+	// Status is a verdict such as "AC" or "WA".
+	Status string
+}
+
+// FetchMySubmissions fetches the submissions of the logged-in user to the
+// contest, following all pages of the list.
+func (c *Client) FetchMySubmissions(ctx context.Context, filter SubmissionFilter) ([]*Submission, error) {
+	contestURL, err := c.contestURL()
+	if err != nil {
+		return nil, err
+	}
+	listURL, err := contestURL.Parse("submissions/me")
+	if err != nil {
+		return nil, err
+	}
+
+	var submissions []*Submission
+	for page, last := 1, 1; page <= last; page++ {
+		q := url.Values{}
+		if filter.TaskID != "" {
+			q.Set("f.Task", filter.TaskID)
+		}
+		if filter.Status != "" {
+			q.Set("f.Status", filter.Status)
+		}
+		q.Set("page", strconv.Itoa(page))
+		listURL.RawQuery = q.Encode()
+
+		slog.InfoContext(ctx, "fetching submissions", slog.String("url", listURL.String()))
+
+		root, err := c.fetchPage(ctx, listURL)
+		if err != nil {
+			return nil, err
+		}
+		for _, tr := range findAllNodes(root, func(n *html.Node) bool {
+			return n.Type == html.ElementNode && n.Data == "tr" && n.Parent != nil && n.Parent.Data == "tbody"
+		}) {
+			s, err := parseSubmissionRow(tr)
+			if err != nil {
+				slog.WarnContext(ctx, "skipped submission row", slog.Int("page", page), slog.String("err", err.Error()))
+				continue
+			}
+			submissions = append(submissions, s)
+		}
+		last = max(last, lastPage(root))
+	}
+	return submissions, nil
+}
+
+// fetchPage fetches and parses the page. It returns ErrNotLoggedIn if the
+// page redirects to the login page.
+func (c *Client) fetchPage(ctx context.Context, pageURL *url.URL) (*html.Node, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", pageURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	// ログインが必要なページはログインページにリダイレクトされる
+	if path.Base(resp.Request.URL.Path) == "login" {
+		return nil, ErrNotLoggedIn
+	}
+	return html.Parse(resp.Body)
+}
+
+// lastPage returns the number of the last page in the pagination of the page,
+// or 1 if there is none.
+func lastPage(root *html.Node) int {
+	last := 1
+	pagination, err := findOneNode(root, func(n *html.Node) bool {
+		return n.Type == html.ElementNode && n.Data == "ul" && hasClass(n, "pagination")
+	})
+	if err != nil {
+		return last
+	}
+	for _, a := range findAllNodes(pagination, func(n *html.Node) bool {
+		return n.Type == html.ElementNode && n.Data == "a"
+	}) {
+		if n, err := strconv.Atoi(strings.TrimSpace(textContent(a))); err == nil {
+			last = max(last, n)
+		}
+	}
+	return last
+}
+
+// parseSubmissionRow parses a row of the submissions page: submission time,
+// task, user, language, score, code size, status, exec time, memory and the
+// link to the detail. The exec time and memory cells are missing for a
+// submission which is not judged.
+func parseSubmissionRow(tr *html.Node) (*Submission, error) {
+	var tds []*html.Node
+	for td := range tr.ChildNodes() {
+		if td.Type == html.ElementNode && td.Data == "td" {
+			tds = append(tds, td)
+		}
+	}
+	if len(tds) < 7 {
+		return nil, fmt.Errorf("unexpected number of cells: %d", len(tds))
+	}
+	cell := func(i int) string { return strings.TrimSpace(textContent(tds[i])) }
+
+	s := &Submission{
+		Task:     cell(1),
+		User:     cell(2),
+		Language: cell(3),
+		Status:   cell(6),
+	}
+
+	submittedAt, err := time.Parse(fixtimeLayout, cell(0))
+	if err != nil {
+		return nil, fmt.Errorf("invalid submission time: %w", err)
+	}
+	s.SubmittedAt = submittedAt
+
+	if a, err := findOneNode(tds[1], func(n *html.Node) bool {
+		return n.Type == html.ElementNode && n.Data == "a"
+	}); err == nil {
+		if href, ok := getAttr(a, "href"); ok {
+			s.TaskID = path.Base(href)
+		}
+	}
+	s.Score, _ = strconv.Atoi(cell(4))
+	s.CodeLength, _ = strconv.Atoi(strings.TrimSuffix(cell(5), " Byte"))
+
+	if len(tds) >= 9 {
+		if s.ExecTimeMS, err = strconv.Atoi(strings.TrimSuffix(cell(7), " ms")); err != nil {
+			return nil, fmt.Errorf("invalid exec time %q", cell(7))
+		}
+		if s.MemoryKB, err = parseMemory(cell(8)); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, a := range findAllNodes(tr, func(n *html.Node) bool {
+		return n.Type == html.ElementNode && n.Data == "a"
+	}) {
+		href, _ := getAttr(a, "href")
+		if m := submissionPathPattern.FindStringSubmatch(href); m != nil {
+			s.ID = m[1]
+			break
+		}
+	}
+	if s.ID == "" {
+		return nil, fmt.Errorf("no link to the submission")
+	}
+	return s, nil
+}
+
+// memoryUnits are the units of the memory shown in the pages, in KB.
+var memoryUnits = map[string]int{
+	"KB": 1, "KiB": 1,
+	"MB": 1024, "MiB": 1024,
+	"GB": 1024 * 1024, "GiB": 1024 * 1024,
+}
+
+// parseMemory parses a memory such as "3748 KiB" into KB.
+func parseMemory(s string) (int, error) {
+	value, unit, _ := strings.Cut(s, " ")
+	n, err := strconv.Atoi(value)
+	k, ok := memoryUnits[strings.TrimSpace(unit)]
+	if err != nil || !ok {
+		return 0, fmt.Errorf("invalid memory %q", s)
+	}
+	return n * k, nil
+}
@@ -0,0 +1,80 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// Submit submits the source code for the task (e.g. "abc350_d") in the
+// language, and returns the URL of the submission.
+func (c *Client) Submit(ctx context.Context, taskID, languageID, source string) (*url.URL, error) {
+	contestURL, err := c.contestURL()
+	if err != nil {
+		return nil, err
+	}
+	submitURL, err := contestURL.Parse("submit")
+	if err != nil {
+		return nil, err
+	}
+
+	csrfToken, err := c.findCSRFToken(ctx, submitURL)
+	if err != nil {
+		return nil, err
+	}
+
+	q := url.Values{}
+	q.Add("data.TaskScreenName", taskID)
+	q.Add("data.LanguageId", languageID)
+	q.Add("sourceCode", source)
+	q.Add("csrf_token", csrfToken)
+
+	slog.InfoContext(ctx, "submitting", slog.String("url", submitURL.String()), slog.String("task", taskID), slog.String("language", languageID))
+
+	req, err := http.NewRequestWithContext(ctx, "POST", submitURL.String(), strings.NewReader(q.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	root, err := html.Parse(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	// 成功すると自分の提出一覧にリダイレクトされ、失敗すると提出ページにエラーが表示される
+	if resp.Request.URL.Path == submitURL.Path {
+		if alert, err := findOneNode(root, func(n *html.Node) bool {
+			return n.Type == html.ElementNode && hasClass(n, "alert-danger")
+		}); err == nil {
+			return nil, fmt.Errorf("submission rejected: %s", strings.Join(strings.Fields(textContent(alert)), " "))
+		}
+		return nil, errors.New("submission rejected")
+	}
+
+	submissionPath := regexp.MustCompile(`^` + regexp.QuoteMeta(contestURL.Path) + `submissions/\d+$`)
+	a, err := findOneNode(root, func(n *html.Node) bool {
+		if n.Type != html.ElementNode || n.Data != "a" {
+			return false
+		}
+		href, ok := getAttr(n, "href")
+		return ok && submissionPath.MatchString(href)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("submitted, but no submission found in %s: %w", resp.Request.URL, err)
+	}
+	href, _ := getAttr(a, "href")
+	return resp.Request.URL.Parse(href)
+}
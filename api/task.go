@@ -4,31 +4,51 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"math"
 	"net/http"
 	"net/url"
 	"path"
+	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	"golang.org/x/net/html"
 )
 
+// TaskSampleIO is a sample of a task, kept byte-exact as in the statement.
 type TaskSampleIO struct {
-	Input  []string
-	Output []string
+	Input  string
+	Output string
 }
 
 type Task struct {
 	URL       *url.URL
 	Index     string
 	SampleIOs []*TaskSampleIO
+	// Statement and Metadata are set by FetchTask.
+	Statement *Statement
+	Metadata  TaskMetadata
+}
+
+// TaskMetadata is the metadata shown on a task page. The fields are zero if
+// not shown, e.g. no score for a heuristic contest.
+type TaskMetadata struct {
+	Title         string `json:"title"`
+	TimeLimitMS   int    `json:"time_limit_ms,omitempty"`
+	MemoryLimitMB int    `json:"memory_limit_mb,omitempty"`
+	Score         int    `json:"score,omitempty"`
+}
+
+// TimeLimit returns the time limit, or zero if unknown.
+func (m TaskMetadata) TimeLimit() time.Duration {
+	return time.Duration(m.TimeLimitMS) * time.Millisecond
 }
 
 func (c *Client) FetchTaskList(ctx context.Context) ([]*Task, error) {
-	taskListURL := &url.URL{
-		Scheme: "https",
-		Host:   DOMAIN,
-		Path:   path.Join("contests", c.family.ContestName(), "tasks"),
+	taskListURL, err := c.baseURL.Parse(path.Join("contests", c.family.ContestName(), "tasks"))
+	if err != nil {
+		return nil, err
 	}
 
 	slog.InfoContext(ctx, "fetching task list", slog.String("url", taskListURL.String()))
@@ -37,7 +57,7 @@ func (c *Client) FetchTaskList(ctx context.Context) ([]*Task, error) {
 	if err != nil {
 		return nil, err
 	}
-	resp, err := c.do(req)
+	resp, err := c.doCached(req)
 	if err != nil {
 		return nil, err
 	}
@@ -100,15 +120,19 @@ func (c *Client) FetchTaskList(ctx context.Context) ([]*Task, error) {
 	return taskList, nil
 }
 
-func (c *Client) FetchSampleIOs(ctx context.Context, task *Task) error {
+// FetchTask fetches the task page, and parses its sample IOs into
+// task.SampleIOs and its statement in the language ("ja" or "en") into
+// task.Statement. If some samples do not pair up, the complete ones are kept
+// and the returned error contains a *SampleError for each of the others.
+func (c *Client) FetchTask(ctx context.Context, task *Task, lang string) error {
 	taskURL := task.URL
-	slog.InfoContext(ctx, "fetching sample IOs", slog.String("url", taskURL.String()))
+	slog.InfoContext(ctx, "fetching task", slog.String("url", taskURL.String()))
 
 	req, err := http.NewRequestWithContext(ctx, "GET", taskURL.String(), nil)
 	if err != nil {
 		return err
 	}
-	resp, err := c.do(req)
+	resp, err := c.doCached(req)
 	if err != nil {
 		return err
 	}
@@ -119,72 +143,73 @@ func (c *Client) FetchSampleIOs(ctx context.Context, task *Task) error {
 		return err
 	}
 
-	_ = findAllNodes(root, func(node *html.Node) bool {
-		if node.Type != html.ElementNode && node.Data != "section" {
-			return false
-		}
-		section := node
+	statement, err := taskStatement(root, lang)
+	if err != nil {
+		return fmt.Errorf("%s: %w", taskURL, err)
+	}
+	task.Metadata = parseTaskMetadata(root, statement)
+	task.Statement = convertStatement(statement, task.Metadata.Title, taskURL)
+	task.SampleIOs, err = parseSampleIOs(statement)
+	if err != nil {
+		return fmt.Errorf("%s: %w", taskURL, err)
+	}
+	return nil
+}
 
-		// section > h3 > (text node) を取得
-		numberNode, err := findOneNode(section, func(node *html.Node) bool {
-			if node.Type != html.TextNode {
-				return false
-			}
-			p := node.Parent
-			if p == nil || p.Type != html.ElementNode || p.Data != "h3" {
-				return false
-			}
-			if node.Parent.Parent != section {
-				return false
-			}
-			return strings.HasPrefix(node.Data, "入力例") ||
-				strings.HasPrefix(node.Data, "出力例")
-		})
-		if err != nil {
-			return false
-		}
-		slog.InfoContext(ctx, "section has example number", slog.String("data", numberNode.Data))
+var (
+	timeLimitPattern   = regexp.MustCompile(`(?:実行時間制限|Time Limit)\s*[:：]\s*([\d.]+)\s*sec`)
+	memoryLimitPattern = regexp.MustCompile(`(?:メモリ制限|Memory Limit)\s*[:：]\s*(\d+)\s*(MB|MiB|KB|KiB)`)
+	scorePattern       = regexp.MustCompile(`^(?:配点|Score)\s*[:：]\s*(\d+)`)
+)
 
-		rawNumber := strings.TrimSpace(strings.TrimLeft(strings.TrimLeft(
-			string([]rune(numberNode.Data)[4:]), "出力例"), "入力例"))
-		var number int
-		if rawNumber != "" {
-			number, err = strconv.Atoi(rawNumber)
-			if err != nil {
-				return false
+// parseTaskMetadata parses the title and the limits shown above the
+// statement, and the score at the top of the statement.
+func parseTaskMetadata(root, statement *html.Node) TaskMetadata {
+	metadata := TaskMetadata{Title: taskTitle(root)}
+	for _, p := range findAllNodes(root, func(n *html.Node) bool {
+		return n.Type == html.ElementNode && n.Data == "p"
+	}) {
+		text := strings.Join(strings.Fields(textContent(p)), " ")
+		if m := timeLimitPattern.FindStringSubmatch(text); m != nil && metadata.TimeLimitMS == 0 {
+			if sec, err := strconv.ParseFloat(m[1], 64); err == nil {
+				metadata.TimeLimitMS = int(math.Round(sec * 1000))
 			}
-		} else {
-			number = 1
-		}
-		if len(task.SampleIOs) < number {
-			task.SampleIOs = append(task.SampleIOs, make([]*TaskSampleIO, number-len(task.SampleIOs))...)
-		}
-		isInput := strings.HasPrefix(numberNode.Data, "入力例")
-		if task.SampleIOs[number-1] == nil {
-			task.SampleIOs[number-1] = &TaskSampleIO{}
 		}
-
-		// section > pre > (text node) を取得
-		exampleNode, err := findOneNode(section, func(node *html.Node) bool {
-			if node.Type != html.TextNode {
-				return false
-			}
-			p := node.Parent
-			if p == nil || p.Type != html.ElementNode || p.Data != "pre" {
-				return false
+		if m := memoryLimitPattern.FindStringSubmatch(text); m != nil && metadata.MemoryLimitMB == 0 {
+			if n, err := strconv.Atoi(m[1]); err == nil {
+				if strings.HasPrefix(m[2], "K") {
+					n /= 1024
+				}
+				metadata.MemoryLimitMB = n
 			}
-			return p.Parent == section
-		})
-		if err != nil {
-			return false
 		}
-		if isInput {
-			task.SampleIOs[number-1].Input = strings.Split(strings.TrimSpace(exampleNode.Data), "\n")
-		} else {
-			task.SampleIOs[number-1].Output = strings.Split(strings.TrimSpace(exampleNode.Data), "\n")
+	}
+	for _, p := range findAllNodes(statement, func(n *html.Node) bool {
+		return n.Type == html.ElementNode && n.Data == "p"
+	}) {
+		if m := scorePattern.FindStringSubmatch(strings.Join(strings.Fields(textContent(p)), " ")); m != nil {
+			metadata.Score, _ = strconv.Atoi(m[1])
+			break
 		}
-		return true
-	})
+	}
+	return metadata
+}
 
-	return nil
+// taskTitle returns the title of the task page, e.g. "A - Welcome to AtCoder".
+// Only the text directly in the heading is used, which also has a link to
+// the editorial.
+func taskTitle(root *html.Node) string {
+	h2, err := findOneNode(root, func(n *html.Node) bool {
+		return n.Type == html.ElementNode && n.Data == "span" && hasClass(n, "h2")
+	})
+	if err != nil {
+		return ""
+	}
+	var sb strings.Builder
+	for c := range h2.ChildNodes() {
+		if c.Type == html.TextNode {
+			sb.WriteString(c.Data)
+		}
+	}
+	return strings.TrimSpace(sb.String())
 }
@@ -0,0 +1,64 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// FetchLocalToolsURL finds the link to the local tools archive of a heuristic
+// contest in the task statement. The archive for Windows is skipped as the
+// source one is usable on every platform.
+func (c *Client) FetchLocalToolsURL(ctx context.Context, task *Task) (*url.URL, error) {
+	slog.InfoContext(ctx, "fetching local tools URL", slog.String("url", task.URL.String()))
+
+	req, err := http.NewRequestWithContext(ctx, "GET", task.URL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.doCached(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	root, err := html.Parse(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	a, err := findOneNode(root, func(n *html.Node) bool {
+		if n.Type != html.ElementNode || n.Data != "a" {
+			return false
+		}
+		href, ok := getAttr(n, "href")
+		return ok && strings.HasSuffix(href, ".zip") && !strings.Contains(href, "windows")
+	})
+	if err != nil {
+		return nil, fmt.Errorf("no local tools link in %s: %w", task.URL, err)
+	}
+	href, _ := getAttr(a, "href")
+	return task.URL.Parse(href)
+}
+
+// Download fetches the content at u.
+func (c *Client) Download(ctx context.Context, u *url.URL) ([]byte, error) {
+	slog.InfoContext(ctx, "downloading", slog.String("url", u.String()))
+
+	req, err := http.NewRequestWithContext(ctx, "GET", u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	return io.ReadAll(resp.Body)
+}
@@ -1,3 +1,20 @@
 package api
 
+import "net/url"
+
+// DOMAIN is the domain of AtCoder, which the cookies of a session belong to.
 const DOMAIN = "atcoder.jp"
+
+// DefaultBaseURL is the URL of the site the client sends requests to.
+const DefaultBaseURL = "https://" + DOMAIN + "/"
+
+// DefaultUserAgent is the User-Agent header of the requests.
+const DefaultUserAgent = "atcoder-cli (+https://github.com/cry999/atcoder-cli)"
+
+func defaultBaseURL() *url.URL {
+	u, err := url.Parse(DefaultBaseURL)
+	if err != nil {
+		panic(err)
+	}
+	return u
+}
@@ -1,18 +1,39 @@
 package main
 
 import (
+	"cmp"
 	"context"
 	"flag"
 	"fmt"
+	"io"
 	"log/slog"
+	"net/http"
+	"net/url"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"text/tabwriter"
+	"time"
 
+	"github.com/cry999/atcoder-cli/api"
 	"github.com/cry999/atcoder-cli/command"
 	"github.com/cry999/atcoder-cli/config"
 	"github.com/cry999/atcoder-cli/contests"
+	_ "github.com/cry999/atcoder-cli/contests/abc"
 	"github.com/cry999/atcoder-cli/contests/adt"
-	"github.com/cry999/atcoder-cli/contests/dp"
+	_ "github.com/cry999/atcoder-cli/contests/agc"
+	"github.com/cry999/atcoder-cli/contests/ahc"
+	_ "github.com/cry999/atcoder-cli/contests/arc"
+	_ "github.com/cry999/atcoder-cli/contests/dp"
+	_ "github.com/cry999/atcoder-cli/contests/generic"
+	_ "github.com/cry999/atcoder-cli/contests/practice"
+)
+
+// Exit codes of submit. exitNotAccepted is used only when the verdict is
+// watched.
+const (
+	exitNotAccepted = 1
+	exitError       = 2
 )
 
 func init() {
@@ -23,19 +44,58 @@ func main() {
 	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
 	defer cancel()
 
+	sessionFile, err := config.SessionFile()
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get session file", slog.String("err", err.Error()))
+		return
+	}
+
+	languagesDir, err := config.LanguagesDir()
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get languages dir", slog.String("err", err.Error()))
+		return
+	}
+
+	httpCacheDir, err := config.HTTPCacheDir()
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get http cache dir", slog.String("err", err.Error()))
+		return
+	}
+
 	config, err := config.LoadConfig(ctx)
 	if err != nil {
 		slog.ErrorContext(ctx, "failed to load config", slog.String("err", err.Error()))
 		return
 	}
 
+	if err := adt.Configure(config.ADT); err != nil {
+		slog.ErrorContext(ctx, "failed to configure ADT", slog.String("err", err.Error()))
+		return
+	}
+	contests.RegisterFlags(flag.CommandLine)
+
 	var (
 		dumpConfig = flag.Bool("dump-config", false, "Dump loaded config and exit")
-		adtLevel   = flag.String("adt-level", string(config.ADT.DefaultLevel), "Default level for ADT problems (easy, medium, hard, all)")
 		testcase   = flag.String("testcase", "all", "Which testcases to run (all, 0, 1, 2, ...)")
+		tasks      = flag.String("tasks", "", "Which tasks to fetch on init (e.g. A,C or 001-010); all by default")
+		seeds      = flag.Int("seeds", 0, "Number of seeds to evaluate for heuristic contests; all by default")
+		upcoming   = flag.Bool("upcoming", false, "List upcoming contests (contests command)")
+		running    = flag.Bool("running", false, "List running contests (contests command)")
+		recent     = flag.Bool("recent", false, "List recently ended contests (contests command)")
+		task       = flag.String("task", "", "Filter submissions by task (submissions command)")
+		status     = flag.String("status", "", "Filter submissions by verdict, e.g. WA (submissions command)")
+		lang       = flag.String("lang", "", "Language of the statements, ja or en (init command); filter submissions by language, e.g. pypy (submissions command)")
+		jsonOutput = flag.Bool("json", false, "Print as JSON (submissions command)")
+		offline    = flag.Bool("offline", false, "Serve the pages only from the cache, e.g. to init a contest fetched before")
+		refresh    = flag.Bool("refresh", false, "Fetch the pages again even if cached recently (implied by init with tasks given)")
+		watch      = flag.Bool("watch", true, "Watch the judge status after submit")
+		cookieFile = flag.String("cookie-file", "", "Log in with cookies exported from a browser (Netscape format, or a REVEL_SESSION value); - reads stdin")
+		jobs       = flag.Int("jobs", config.AHC.Jobs, "Number of seeds evaluated in parallel for heuristic contests; the number of CPUs by default")
 		verbose    = flag.Bool("v", false, "Enable verbose logging")
 	)
 	flag.Parse()
+	args := parseInterspersed(flag.CommandLine, flag.Args())
+	arg := func(i int) string { return contests.Arg(args, i) }
 
 	if *dumpConfig {
 		if err := config.Dump(os.Stdout); err != nil {
@@ -44,73 +104,355 @@ func main() {
 		return
 	}
 
-	contestFamily := flag.Arg(1)
-	if contestFamily == "" {
-		slog.ErrorContext(ctx, "contest family argument is required")
+	session, err := api.LoadSession(sessionFile)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to load session", slog.String("file", sessionFile), slog.String("err", err.Error()))
+		return
+	}
+	clientOpts, err := httpClientOptions(config.HTTP)
+	if err != nil {
+		slog.ErrorContext(ctx, "invalid http config", slog.String("err", err.Error()))
 		return
 	}
+	cache := api.NewCache(httpCacheDir)
+	if config.HTTP.CacheMaxAge > 0 {
+		cache.SetMaxAge(config.HTTP.CacheMaxAge)
+	}
+	clientOpts = append(clientOpts, api.WithSession(session), api.WithCache(cache))
+	if *offline {
+		clientOpts = append(clientOpts, api.WithOffline())
+	}
+	if *refresh {
+		clientOpts = append(clientOpts, api.WithRefresh())
+	}
 
-	var family contests.Family
-	var taskIndex string
-	switch contestFamily {
-	// AtCoder Daily Training
-	case "adt":
-		family, err = adt.New(flag.Arg(2), flag.Arg(3), *adtLevel)
-		if err != nil {
-			slog.ErrorContext(
-				ctx, "failed to parse ADT family",
-				slog.String("date", flag.Arg(2)),
-				slog.String("time", flag.Arg(3)),
-				slog.String("err", err.Error()),
-			)
-			return
+	switch {
+	case arg(0) == "login" && *cookieFile != "":
+		r := os.Stdin
+		if *cookieFile != "-" {
+			f, err := os.Open(*cookieFile)
+			if err != nil {
+				slog.ErrorContext(ctx, "failed to open cookie file", slog.String("file", *cookieFile), slog.String("err", err.Error()))
+				return
+			}
+			defer f.Close()
+			r = f
 		}
-		taskIndex = flag.Arg(4)
-	case "dp":
-		family, err = dp.New()
-		if err != nil {
-			slog.ErrorContext(
-				ctx, "failed to parse ADT family",
-				slog.String("err", err.Error()),
-			)
-			return
+		if err := command.ImportSession(ctx, os.Stdout, r, session, clientOpts...); err != nil {
+			slog.ErrorContext(ctx, "failed to import session", slog.String("err", err.Error()))
+		}
+		return
+	case arg(0) == "login":
+		sources := []command.CredentialSource{
+			command.CredentialsFromEnv(),
+			command.CredentialsFromHelper(config.Login.CredentialHelper),
+			command.CredentialsFromUsername(config.Login.Username),
+			command.CredentialsFromPrompt(os.Stdin, os.Stderr),
+		}
+		if err := command.Login(ctx, os.Stdout, sources, clientOpts...); err != nil {
+			slog.ErrorContext(ctx, "failed to log in", slog.String("err", err.Error()))
+		}
+		return
+	case arg(0) == "logout":
+		if err := command.Logout(ctx, os.Stdout, session); err != nil {
+			slog.ErrorContext(ctx, "failed to log out", slog.String("err", err.Error()))
+		}
+		return
+	case arg(0) == "whoami":
+		if err := command.Whoami(ctx, os.Stdout, session, clientOpts...); err != nil {
+			slog.ErrorContext(ctx, "failed to get the logged-in user", slog.String("err", err.Error()))
+		}
+		return
+	case arg(0) == "families":
+		printFamilies(os.Stdout)
+		return
+	case arg(0) == "contests":
+		var sections []api.ContestListSection
+		if *running {
+			sections = append(sections, api.ContestsRunning)
+		}
+		if *upcoming {
+			sections = append(sections, api.ContestsUpcoming)
+		}
+		if *recent {
+			sections = append(sections, api.ContestsRecent)
+		}
+		if len(sections) == 0 {
+			sections = []api.ContestListSection{api.ContestsRunning, api.ContestsUpcoming, api.ContestsRecent}
+		}
+		if err := command.ListContests(ctx, os.Stdout, sections, clientOpts...); err != nil {
+			slog.ErrorContext(ctx, "failed to list contests", slog.String("err", err.Error()))
+		}
+		return
+	case arg(0) == "langs":
+		mappings := map[string]command.LanguageMapping{}
+		for name, lang := range config.Languages {
+			mappings[name] = command.LanguageMapping{ID: lang.ID, Pattern: lang.Pattern}
+		}
+		if err := command.ListLanguages(ctx, os.Stdout, languagesDir, mappings, clientOpts...); err != nil {
+			slog.ErrorContext(ctx, "failed to list languages", slog.String("err", err.Error()))
+		}
+		return
+	case arg(0) == "adt" && arg(1) == "schedule":
+		printADTSchedule(os.Stdout, time.Now())
+		return
+	}
+
+	// submit は失敗を終了コードで伝える
+	failed := func() {
+		if arg(0) == "submit" {
+			cancel()
+			os.Exit(exitError)
 		}
-		taskIndex = flag.Arg(2)
-	default:
-		slog.ErrorContext(ctx, "unknown contest type", slog.String("contest", contestFamily))
+	}
+
+	contestFamily := arg(1)
+	if contestFamily == "" {
+		slog.ErrorContext(ctx, "contest family argument is required")
+		failed()
+		return
+	}
+
+	if err := contests.CheckFlags(flag.CommandLine, contestFamily); err != nil {
+		slog.ErrorContext(ctx, "invalid flags", slog.String("family", contestFamily), slog.String("err", err.Error()))
+		failed()
+		return
+	}
+
+	family, taskIndex, err := contests.Resolve(contestFamily, args[2:])
+	if err != nil {
+		slog.ErrorContext(
+			ctx, "failed to parse contest family",
+			slog.String("family", contestFamily),
+			slog.Any("args", args[2:]),
+			slog.String("err", err.Error()),
+		)
+		failed()
+		return
+	}
+
+	if (arg(0) == "test" || arg(0) == "submit") && taskIndex == "" {
+		fmt.Printf("task index argument is required for %s command\n", arg(0))
+		failed()
 		return
 	}
 
-	cmd, err := command.NewCommand(ctx, family, config.WorkDir)
+	members := contests.Members(family)
+	if (arg(0) == "test" || arg(0) == "submit") && len(members) > 1 {
+		slog.ErrorContext(ctx, "a single contest is required", slog.String("command", arg(0)), slog.String("contest", family.ContestName()))
+		failed()
+		return
+	}
+
+	// 問題を指定した init は問題文の修正を取り直すことが多いので、キャッシュを使わずに確認する
+	if arg(0) == "init" && (taskIndex != "" || *tasks != "") && !*refresh {
+		clientOpts = append(clientOpts, api.WithRefresh())
+	}
+
+	// 各メンバーで作業ディレクトリを移動するので、相対パスは先に解決しておく
+	workdir, err := filepath.Abs(config.WorkDir)
 	if err != nil {
-		slog.ErrorContext(ctx, "failed to create command", slog.String("err", err.Error()))
+		slog.ErrorContext(ctx, "failed to resolve workdir", slog.String("workdir", config.WorkDir), slog.String("err", err.Error()))
+		failed()
 		return
 	}
 
-	switch flag.Arg(0) {
-	case "init":
-		if err := cmd.FetchSampleIO(ctx); err != nil {
-			slog.ErrorContext(ctx, "failed to fetch sample IO", slog.String("err", err.Error()))
+	for _, member := range members {
+		cmd, err := command.NewCommand(ctx, member, workdir, command.WithClientOptions(clientOpts...))
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to create command", slog.String("contest", member.ContestName()), slog.String("err", err.Error()))
+			failed()
 			return
 		}
-	case "test":
-		if taskIndex == "" {
-			fmt.Println("task index argument is required for test command")
+
+		switch arg(0) {
+		case "init":
+			var opts command.FetchOptions
+			if *tasks != "" {
+				opts = append(opts, command.FetchWithTasks(*tasks))
+			}
+			if taskIndex != "" {
+				opts = append(opts, command.FetchWithTasks(taskIndex))
+			}
+			if *lang != "" {
+				opts = append(opts, command.FetchWithLanguage(*lang))
+			}
+			if err := cmd.FetchSampleIO(ctx, opts...); err != nil {
+				slog.ErrorContext(ctx, "failed to fetch sample IO", slog.String("contest", member.ContestName()), slog.String("err", err.Error()))
+				return
+			}
+			if _, err := cmd.FetchContest(ctx); err != nil {
+				slog.WarnContext(ctx, "failed to fetch contest metadata", slog.String("contest", member.ContestName()), slog.String("err", err.Error()))
+			}
+			if contests.IsHeuristic(member) {
+				if err := cmd.FetchTools(ctx); err != nil {
+					slog.ErrorContext(ctx, "failed to fetch local tools", slog.String("contest", member.ContestName()), slog.String("err", err.Error()))
+					return
+				}
+			}
+		case "test":
+			if contests.IsHeuristic(member) {
+				opts := command.ScoreOptions{
+					command.ScoreWithSeeds(*seeds),
+					command.ScoreWithJobs(*jobs),
+					command.ScoreWithBuild(config.AHC.Build),
+					command.ScoreWithScorer(config.AHC.Scorer),
+				}
+				if config.AHC.Objective == ahc.ObjectiveMin {
+					opts = append(opts, command.ScoreWithMinimize())
+				}
+				if err := cmd.RunScore(ctx, cmd.TaskIndex(taskIndex), opts...); err != nil {
+					slog.ErrorContext(ctx, "failed to run scoring", slog.String("contest", member.ContestName()), slog.String("err", err.Error()))
+					return
+				}
+				continue
+			}
+			var opts command.TestOptions
+			if *testcase != "all" {
+				opts = append(opts, command.TestWithTestcase(*testcase))
+			}
+			if *verbose {
+				opts = append(opts, command.TestWithVerbose())
+			}
+			if err := cmd.RunTest(ctx, cmd.TaskIndex(taskIndex), opts...); err != nil {
+				slog.ErrorContext(ctx, "failed to run tests", slog.String("contest", member.ContestName()), slog.String("err", err.Error()))
+				return
+			}
+		case "submit":
+			lang, ok := config.Languages[config.Language]
+			if !ok || (lang.ID == "" && lang.Pattern == "") {
+				slog.ErrorContext(ctx, "no language ID or pattern is configured", slog.String("language", config.Language))
+				cancel()
+				os.Exit(exitError)
+			}
+			languageID, err := cmd.ResolveLanguage(ctx, languagesDir, command.LanguageMapping{ID: lang.ID, Pattern: lang.Pattern})
+			if err != nil {
+				slog.ErrorContext(ctx, "failed to resolve language", slog.String("language", config.Language), slog.String("err", err.Error()))
+				cancel()
+				os.Exit(exitError)
+			}
+			submissionID, err := cmd.Submit(ctx, os.Stdout, cmd.TaskIndex(taskIndex), languageID)
+			if err != nil {
+				slog.ErrorContext(ctx, "failed to submit", slog.String("contest", member.ContestName()), slog.String("err", err.Error()))
+				cancel()
+				os.Exit(exitError)
+			}
+			if !*watch {
+				continue
+			}
+			accepted, err := cmd.Watch(ctx, os.Stdout, submissionID)
+			if err != nil {
+				slog.ErrorContext(ctx, "failed to watch submission", slog.String("submission", submissionID), slog.String("err", err.Error()))
+				cancel()
+				os.Exit(exitError)
+			}
+			if !accepted {
+				cancel()
+				os.Exit(exitNotAccepted)
+			}
+		case "submissions":
+			var opts command.SubmissionsOptions
+			if t := cmp.Or(*task, taskIndex); t != "" {
+				opts = append(opts, command.SubmissionsWithTask(cmd.TaskIndex(t)))
+			}
+			if *status != "" {
+				opts = append(opts, command.SubmissionsWithStatus(*status))
+			}
+			if *lang != "" {
+				opts = append(opts, command.SubmissionsWithLanguage(*lang))
+			}
+			if *jsonOutput {
+				opts = append(opts, command.SubmissionsWithJSON())
+			}
+			if err := cmd.ListSubmissions(ctx, os.Stdout, opts...); err != nil {
+				slog.ErrorContext(ctx, "failed to list submissions", slog.String("contest", member.ContestName()), slog.String("err", err.Error()))
+				return
+			}
+		case "info":
+			if err := cmd.Info(ctx, os.Stdout); err != nil {
+				slog.ErrorContext(ctx, "failed to show contest info", slog.String("contest", member.ContestName()), slog.String("err", err.Error()))
+				return
+			}
+		default:
+			slog.ErrorContext(ctx, "unknown command", slog.String("command", arg(0)))
 			return
 		}
-		var opts command.TestOptions
-		if *testcase != "all" {
-			opts = append(opts, command.TestWithTestcase(*testcase))
+	}
+}
+
+// parseInterspersed parses the flags placed among the positional arguments
+// (e.g. "init adt latest --level hard") and returns the positional ones.
+func parseInterspersed(fs *flag.FlagSet, args []string) []string {
+	var positional []string
+	for len(args) > 0 {
+		positional = append(positional, args[0])
+		rest := args[1:]
+		// errors are handled by fs according to its ErrorHandling
+		_ = fs.Parse(rest)
+		args = fs.Args()
+		if consumed := len(rest) - len(args); consumed > 0 && rest[consumed-1] == "--" {
+			return append(positional, args...)
 		}
-		if *verbose {
-			opts = append(opts, command.TestWithVerbose())
+	}
+	return positional
+}
+
+// httpClientOptions returns the options of the API clients from the config.
+func httpClientOptions(cfg config.HTTP) ([]api.ClientOption, error) {
+	var opts []api.ClientOption
+	if cfg.BaseURL != "" {
+		u, err := url.Parse(cfg.BaseURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid base_url: %w", err)
 		}
-		if err := cmd.RunTest(ctx, taskIndex, opts...); err != nil {
-			slog.ErrorContext(ctx, "failed to run tests", slog.String("err", err.Error()))
-			return
+		opts = append(opts, api.WithBaseURL(u))
+	}
+	if cfg.UserAgent != "" {
+		opts = append(opts, api.WithUserAgent(cfg.UserAgent))
+	}
+	if cfg.Proxy != "" {
+		u, err := url.Parse(cfg.Proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy: %w", err)
 		}
-	default:
-		slog.ErrorContext(ctx, "unknown command", slog.String("command", flag.Arg(0)))
-		return
+		opts = append(opts, api.WithProxy(u))
+	}
+	if cfg.Rate > 0 || cfg.Burst > 0 {
+		opts = append(opts, api.WithRateLimit(cmp.Or(cfg.Rate, api.DefaultRate), cmp.Or(cfg.Burst, api.DefaultBurst)))
+	}
+	if cfg.RetryBudget > 0 {
+		opts = append(opts, api.WithRetryBudget(cfg.RetryBudget))
+	}
+	if cfg.Timeout > 0 {
+		opts = append(opts, api.WithHTTPClient(&http.Client{Timeout: cfg.Timeout}))
+	}
+	return opts, nil
+}
+
+func printFamilies(w io.Writer) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	defer tw.Flush()
+	for _, r := range contests.Registrations() {
+		fmt.Fprintf(tw, "%s %s\t%s\n", r.Name, r.Usage, r.Help)
+	}
+}
+
+// adtScheduleSessions is the number of sessions printed by "adt schedule".
+const adtScheduleSessions = 9
+
+func printADTSchedule(w io.Writer, now time.Time) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	defer tw.Flush()
+	for _, s := range adt.Upcoming(now, adtScheduleSessions) {
+		status := ""
+		if !now.Before(s.Start()) {
+			status = "(now)"
+		}
+		fmt.Fprintf(
+			tw, "%s\t%s-%s\t#%d\tadt %s\t%s\n",
+			s.Start().Format("2006-01-02 Mon"),
+			s.Start().Format("15:04"), s.End().Format("15:04 MST"),
+			s.Number(), s, status,
+		)
 	}
 }
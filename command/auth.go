@@ -0,0 +1,217 @@
+package command
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/x/term"
+	"github.com/cry999/atcoder-cli/api"
+)
+
+// Environment variables providing the credentials.
+const (
+	EnvUsername = "ATCODER_USERNAME"
+	EnvPassword = "ATCODER_PASSWORD"
+)
+
+// Credentials are the username and password to log in with.
+type Credentials struct {
+	Username string
+	Password string
+}
+
+func (c Credentials) complete() bool {
+	return c.Username != "" && c.Password != ""
+}
+
+// CredentialSource fills the missing fields of the credentials.
+type CredentialSource func(ctx context.Context, creds *Credentials) error
+
+// CredentialsFromEnv reads the credentials from ATCODER_USERNAME and
+// ATCODER_PASSWORD.
+func CredentialsFromEnv() CredentialSource {
+	return func(ctx context.Context, creds *Credentials) error {
+		if creds.Username == "" {
+			creds.Username = os.Getenv(EnvUsername)
+		}
+		if creds.Password == "" {
+			creds.Password = os.Getenv(EnvPassword)
+		}
+		return nil
+	}
+}
+
+// CredentialsFromUsername provides a fixed username, e.g. from the config.
+func CredentialsFromUsername(username string) CredentialSource {
+	return func(ctx context.Context, creds *Credentials) error {
+		if creds.Username == "" {
+			creds.Username = username
+		}
+		return nil
+	}
+}
+
+// CredentialsFromHelper runs a credential helper command, which prints
+// "username=..." and "password=..." lines. A line without "=" is taken as
+// the password, so that e.g. `pass show atcoder` can be used as it is.
+func CredentialsFromHelper(helper []string) CredentialSource {
+	return func(ctx context.Context, creds *Credentials) error {
+		if len(helper) == 0 {
+			return nil
+		}
+		var stdout bytes.Buffer
+		cmd := exec.CommandContext(ctx, helper[0], helper[1:]...)
+		cmd.Stdout = &stdout
+		cmd.Stderr = os.Stderr
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("credential helper failed: %w", err)
+		}
+
+		var username, password string
+		for line := range strings.Lines(stdout.String()) {
+			line = strings.TrimRight(line, "\r\n")
+			key, value, ok := strings.Cut(line, "=")
+			switch {
+			case ok && key == "username":
+				username = value
+			case ok && key == "password":
+				password = value
+			case !ok && password == "" && line != "":
+				password = line
+			}
+		}
+		if creds.Username == "" {
+			creds.Username = username
+		}
+		if creds.Password == "" {
+			creds.Password = password
+		}
+		return nil
+	}
+}
+
+// CredentialsFromPrompt asks for the missing credentials on the terminal.
+// The password is not echoed.
+func CredentialsFromPrompt(in *os.File, out io.Writer) CredentialSource {
+	return func(ctx context.Context, creds *Credentials) error {
+		if !term.IsTerminal(in.Fd()) {
+			return nil
+		}
+		if creds.Username == "" {
+			fmt.Fprint(out, "Username: ")
+			line, err := bufio.NewReader(in).ReadString('\n')
+			if err != nil && !errors.Is(err, io.EOF) {
+				return err
+			}
+			creds.Username = strings.TrimSpace(line)
+		}
+		if creds.Password == "" {
+			fmt.Fprint(out, "Password: ")
+			password, err := term.ReadPassword(in.Fd())
+			fmt.Fprintln(out)
+			if err != nil {
+				return err
+			}
+			creds.Password = string(password)
+		}
+		return nil
+	}
+}
+
+// Login logs in to AtCoder with the credentials from the sources, tried in
+// order until both the username and the password are known. The session is
+// kept by the session given to the client options.
+func Login(ctx context.Context, w io.Writer, sources []CredentialSource, opts ...api.ClientOption) error {
+	var creds Credentials
+	for _, source := range sources {
+		if creds.complete() {
+			break
+		}
+		if err := source(ctx, &creds); err != nil {
+			return err
+		}
+	}
+	if !creds.complete() {
+		return fmt.Errorf("no credentials; set %s and %s, configure a credential helper, or run in a terminal", EnvUsername, EnvPassword)
+	}
+
+	client := api.NewClient(nil, opts...)
+	defer client.Shutdown()
+
+	if err := client.Login(ctx, creds.Username, creds.Password); err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "%s logged in as %s\n", styleAC.Render("OK"), creds.Username)
+	return nil
+}
+
+// sessionExpiryWarning is how long before the expiry of the session a warning
+// is shown.
+const sessionExpiryWarning = 14 * 24 * time.Hour
+
+// ImportSession logs in with the cookies exported from a browser, read from
+// r either in the Netscape cookie file format or as a bare REVEL_SESSION
+// value.
+func ImportSession(ctx context.Context, w io.Writer, r io.Reader, session *api.Session, opts ...api.ClientOption) error {
+	client := api.NewClient(nil, slices.Concat(opts, []api.ClientOption{api.WithSession(session)})...)
+	defer client.Shutdown()
+
+	cookies, err := client.ParseCookies(r)
+	if err != nil {
+		return err
+	}
+
+	user, err := client.ImportSession(ctx, cookies)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "%s logged in as %s\n", styleAC.Render("OK"), user)
+	warnSessionExpiry(w, session)
+	return nil
+}
+
+// warnSessionExpiry warns if the session expires soon.
+func warnSessionExpiry(w io.Writer, session *api.Session) {
+	expiresAt, ok := session.ExpiresAt()
+	if !ok {
+		return
+	}
+	if left := time.Until(expiresAt); left < sessionExpiryWarning {
+		fmt.Fprintf(
+			w, "%s the session expires at %s (in %s); log in again soon\n",
+			styleWA.Render("WARN"), expiresAt.Local().Format(time.DateTime), left.Round(time.Hour),
+		)
+	}
+}
+
+// Logout discards the saved session.
+func Logout(ctx context.Context, w io.Writer, session *api.Session) error {
+	if err := session.Clear(); err != nil {
+		return err
+	}
+	fmt.Fprintln(w, "logged out")
+	return nil
+}
+
+// Whoami prints the name of the logged-in user.
+func Whoami(ctx context.Context, w io.Writer, session *api.Session, opts ...api.ClientOption) error {
+	client := api.NewClient(nil, slices.Concat(opts, []api.ClientOption{api.WithSession(session)})...)
+	defer client.Shutdown()
+
+	user, err := client.Whoami(ctx)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(w, user)
+	warnSessionExpiry(w, session)
+	return nil
+}
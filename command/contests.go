@@ -0,0 +1,58 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/cry999/atcoder-cli/api"
+)
+
+var contestListTitles = map[api.ContestListSection]string{
+	api.ContestsRunning:  "Running",
+	api.ContestsUpcoming: "Upcoming",
+	api.ContestsRecent:   "Recent",
+}
+
+// ListContests prints the contests in the sections of the contest list. The
+// printed IDs can be passed to init as they are.
+func ListContests(ctx context.Context, w io.Writer, sections []api.ContestListSection, opts ...api.ClientOption) error {
+	client := api.NewClient(nil, opts...)
+	defer client.Shutdown()
+
+	list, err := client.FetchContestList(ctx, sections...)
+	if err != nil {
+		return err
+	}
+
+	for i, section := range sections {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintln(w, styleTitle.Render(contestListTitles[section]))
+		if len(list[section]) == 0 {
+			fmt.Fprintln(w, "(none)")
+			continue
+		}
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "ID\tStart\tDuration\tRated\tTitle")
+		for _, c := range list[section] {
+			fmt.Fprintf(
+				tw, "%s\t%s\t%s\t%s\t%s\n",
+				c.ID, c.StartAt.Local().Format("2006-01-02 15:04 MST"), formatDuration(c.Duration), c.RatedRange, c.Title,
+			)
+		}
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// formatDuration formats d like the contest list does (e.g. "01:40").
+func formatDuration(d time.Duration) string {
+	d = d.Round(time.Minute)
+	return fmt.Sprintf("%02d:%02d", int(d.Hours()), int(d.Minutes())%60)
+}
@@ -5,14 +5,25 @@ import (
 	"log/slog"
 	"os"
 
+	"github.com/cry999/atcoder-cli/api"
 	"github.com/cry999/atcoder-cli/contests"
 )
 
 type Command struct {
-	family contests.Family
+	family        contests.Family
+	clientOptions []api.ClientOption
 }
 
-func NewCommand(ctx context.Context, family contests.Family, workdir string) (*Command, error) {
+type Option func(*Command)
+
+// WithClientOptions sets the options of the API clients used by the command.
+func WithClientOptions(opts ...api.ClientOption) Option {
+	return func(c *Command) {
+		c.clientOptions = append(c.clientOptions, opts...)
+	}
+}
+
+func NewCommand(ctx context.Context, family contests.Family, workdir string, opts ...Option) (*Command, error) {
 	baseDir := family.BaseDir(workdir)
 	if err := os.MkdirAll(baseDir, 0755); err != nil {
 		slog.ErrorContext(ctx, "failed to create working directory", slog.String("dir", baseDir), slog.String("err", err.Error()))
@@ -23,7 +34,15 @@ func NewCommand(ctx context.Context, family contests.Family, workdir string) (*C
 		return nil, err
 	}
 
-	return &Command{
+	c := &Command{
 		family: family,
-	}, nil
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
+}
+
+func (c *Command) newClient() *api.Client {
+	return api.NewClient(c.family, c.clientOptions...)
 }
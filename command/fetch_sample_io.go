@@ -2,42 +2,192 @@ package command
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"net/http"
 	"os"
 	"path/filepath"
-	"strings"
+	"sync"
 
 	"github.com/cry999/atcoder-cli/api"
 )
 
-func (c *Command) FetchSampleIO(ctx context.Context) error {
-	client := api.NewClient(c.family)
+type FetchOptions []FetchOption
+
+type FetchOption func(*fetchConfig)
+
+type fetchConfig struct {
+	tasks string
+	lang  string
+}
+
+// fetchJobs is the number of task pages fetched in parallel. The requests
+// are limited by the rate limit of the client anyway, so more workers would
+// only wait for it.
+const fetchJobs = 4
+
+// FetchWithTasks restricts the tasks to fetch to a comma separated list of
+// task indexes or ranges of them (e.g. "001-010,015"). It can be given more
+// than once.
+func FetchWithTasks(tasks string) FetchOption {
+	return func(fc *fetchConfig) {
+		if fc.tasks != "" {
+			tasks = fc.tasks + "," + tasks
+		}
+		fc.tasks = tasks
+	}
+}
+
+// FetchWithLanguage sets the language of the statements, "ja" or "en".
+func FetchWithLanguage(lang string) FetchOption {
+	return func(fc *fetchConfig) {
+		fc.lang = lang
+	}
+}
+
+// FetchSampleIO fetches the sample IOs and the statements of the tasks in
+// parallel and writes each task as soon as it arrives. A failure on a task
+// does not stop the others; the errors are returned together.
+func (c *Command) FetchSampleIO(ctx context.Context, opts ...FetchOption) error {
+	cfg := fetchConfig{lang: "ja"}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if cfg.lang != "ja" && cfg.lang != "en" {
+		return fmt.Errorf("unknown statement language %q; ja or en", cfg.lang)
+	}
+
+	client := c.newClient()
 	defer client.Shutdown()
 
-	tasks, err := client.FetchTaskList(ctx)
+	// the cached list is enough to find the tasks to fetch
+	cached := cfg.tasks != ""
+	tasks, err := c.fetchTaskList(ctx, client, cached)
 	if err != nil {
 		return err
 	}
-	for _, task := range tasks {
-		if err := os.Mkdir(task.Index, 0755); err != nil && !os.IsExist(err) {
-			slog.ErrorContext(ctx, "failed to create task directory", slog.String("dir", task.Index), slog.String("err", err.Error()))
+	selected, err := selectTasks(c.family, tasks, cfg.tasks)
+	if err != nil && cached {
+		// 保存した一覧にない問題は、一覧を取り直してからもう一度探す
+		slog.InfoContext(ctx, "refetching task list", slog.String("err", err.Error()))
+		if tasks, err = c.fetchTaskList(ctx, client, false); err != nil {
 			return err
 		}
-		err = client.FetchSampleIOs(ctx, task)
-		if err != nil {
-			slog.ErrorContext(ctx, "failed to fetch sample IOs", slog.String("err", err.Error()))
-			return err
-		}
-		for i, io := range task.SampleIOs {
-			if err := os.WriteFile(filepath.Join(task.Index, fmt.Sprintf("input-%02d.txt", i)), []byte(strings.Join(io.Input, "\n")+"\n"), 0644); err != nil {
-				slog.ErrorContext(ctx, "failed to write sample input file", slog.String("file", filepath.Join(task.Index, fmt.Sprintf("input-%s.txt", task.Index))), slog.String("err", err.Error()))
-			}
-			if err := os.WriteFile(filepath.Join(task.Index, fmt.Sprintf("output-%02d.txt", i)), []byte(strings.Join(io.Output, "\n")+"\n"), 0644); err != nil {
-				slog.ErrorContext(ctx, "failed to write sample output file", slog.String("file", filepath.Join(task.Index, fmt.Sprintf("output-%s.txt", task.Index))), slog.String("err", err.Error()))
-			}
+		selected, err = selectTasks(c.family, tasks, cfg.tasks)
+	}
+	if err != nil {
+		return err
+	}
+	tasks = selected
+	// 取得できた問題から順に書き出し、失敗した問題があっても他は続ける
+	errs := make([]error, len(tasks))
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, fetchJobs)
+	for i, task := range tasks {
+		wg.Go(func() {
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			errs[i] = c.fetchTask(ctx, client, task, cfg.lang)
+		})
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// fetchTaskList returns the task list, hinting why it is missing.
+func (c *Command) fetchTaskList(ctx context.Context, client *api.Client, cached bool) ([]*api.Task, error) {
+	tasks, err := c.taskList(ctx, client, cached)
+	if err != nil {
+		var statusErr *api.StatusError
+		if errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotFound {
+			return nil, fmt.Errorf("no task list; the contest may not have started yet: %w", err)
 		}
+		return nil, err
 	}
+	return tasks, nil
+}
 
+// fetchTask fetches the task page and writes the sample IOs and the statement
+// into the task directory.
+func (c *Command) fetchTask(ctx context.Context, client *api.Client, task *api.Task, lang string) error {
+	if err := os.Mkdir(task.Index, 0755); err != nil && !os.IsExist(err) {
+		slog.ErrorContext(ctx, "failed to create task directory", slog.String("dir", task.Index), slog.String("err", err.Error()))
+		return err
+	}
+	// 組にならない例があっても揃っている例は書き出す
+	err := client.FetchTask(ctx, task, lang)
+	var sampleErr *api.SampleError
+	if err != nil && !errors.As(err, &sampleErr) {
+		var statusErr *api.StatusError
+		if errors.As(err, &statusErr) {
+			slog.ErrorContext(
+				ctx, "failed to fetch sample IOs",
+				slog.String("task", task.Index),
+				slog.String("url", statusErr.URL),
+				slog.Int("status_code", statusErr.StatusCode),
+			)
+		} else {
+			slog.ErrorContext(ctx, "failed to fetch sample IOs", slog.String("task", task.Index), slog.String("err", err.Error()))
+		}
+		return fmt.Errorf("task %s: %w", task.Index, err)
+	}
+	if len(task.SampleIOs) == 0 && err == nil {
+		slog.WarnContext(ctx, "no sample IOs found", slog.String("task", task.Index), slog.String("url", task.URL.String()))
+	}
+	for i, io := range task.SampleIOs {
+		inputFile := filepath.Join(task.Index, fmt.Sprintf("input-%02d.txt", i))
+		if err := os.WriteFile(inputFile, []byte(io.Input), 0644); err != nil {
+			slog.ErrorContext(ctx, "failed to write sample input file", slog.String("file", inputFile), slog.String("err", err.Error()))
+		}
+		outputFile := filepath.Join(task.Index, fmt.Sprintf("output-%02d.txt", i))
+		if err := os.WriteFile(outputFile, []byte(io.Output), 0644); err != nil {
+			slog.ErrorContext(ctx, "failed to write sample output file", slog.String("file", outputFile), slog.String("err", err.Error()))
+		}
+	}
+	if task.Statement != nil {
+		writeStatement(ctx, client, task)
+	}
+	if err := saveTaskMetadata(task); err != nil {
+		slog.ErrorContext(ctx, "failed to write task metadata", slog.String("task", task.Index), slog.String("err", err.Error()))
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "sample IOs do not pair up", slog.String("task", task.Index), slog.String("err", err.Error()))
+		return fmt.Errorf("task %s: %w", task.Index, err)
+	}
+	slog.InfoContext(ctx, "fetched sample IOs", slog.String("task", task.Index), slog.Int("samples", len(task.SampleIOs)))
 	return nil
 }
+
+// problemFile is the statement of a task in Markdown, in the task directory.
+const problemFile = "problem.md"
+
+// writeStatement writes the statement of the task and downloads its images.
+// The images downloaded before are kept, so that init works offline.
+func writeStatement(ctx context.Context, client *api.Client, task *api.Task) {
+	file := filepath.Join(task.Index, problemFile)
+	if err := os.WriteFile(file, []byte(task.Statement.Markdown), 0644); err != nil {
+		slog.ErrorContext(ctx, "failed to write statement", slog.String("file", file), slog.String("err", err.Error()))
+		return
+	}
+	for _, image := range task.Statement.Images {
+		imageFile := filepath.Join(task.Index, filepath.FromSlash(image.Path))
+		if _, err := os.Stat(imageFile); err == nil {
+			continue
+		}
+		data, err := client.Download(ctx, image.URL)
+		if err != nil {
+			slog.WarnContext(ctx, "failed to download image", slog.String("url", image.URL.String()), slog.String("err", err.Error()))
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(imageFile), 0755); err != nil {
+			slog.WarnContext(ctx, "failed to create image directory", slog.String("file", imageFile), slog.String("err", err.Error()))
+			continue
+		}
+		if err := os.WriteFile(imageFile, data, 0644); err != nil {
+			slog.WarnContext(ctx, "failed to write image", slog.String("file", imageFile), slog.String("err", err.Error()))
+		}
+	}
+}
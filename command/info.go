@@ -0,0 +1,125 @@
+package command
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/cry999/atcoder-cli/contests"
+)
+
+// contestFile keeps the metadata of the contest in its working directory.
+const contestFile = "contest.json"
+
+// FetchContest fetches the metadata of the contest and saves it in the
+// working directory.
+func (c *Command) FetchContest(ctx context.Context) (*contests.Contest, error) {
+	client := c.newClient()
+	defer client.Shutdown()
+
+	contest, err := client.FetchContest(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// the schedule of the family is used when the page does not show one
+	if s, ok := c.family.(contests.Scheduled); ok && contest.StartAt.IsZero() {
+		contest.StartAt, contest.Duration = s.Schedule()
+	}
+
+	tasks, err := c.taskList(ctx, client, true)
+	if err != nil {
+		slog.WarnContext(ctx, "failed to get task list", slog.String("err", err.Error()))
+	}
+	for _, t := range tasks {
+		contest.Tasks = append(contest.Tasks, contests.Task{Index: t.Index, ID: path.Base(t.URL.Path)})
+	}
+
+	data, err := json.MarshalIndent(contest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(contestFile, data, 0644); err != nil {
+		slog.ErrorContext(ctx, "failed to write contest file", slog.String("file", contestFile), slog.String("err", err.Error()))
+		return nil, err
+	}
+	return contest, nil
+}
+
+// Contest returns the metadata of the contest saved in the working directory,
+// fetching it if there is none.
+func (c *Command) Contest(ctx context.Context) (*contests.Contest, error) {
+	data, err := os.ReadFile(contestFile)
+	if os.IsNotExist(err) {
+		return c.FetchContest(ctx)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var contest contests.Contest
+	if err := json.Unmarshal(data, &contest); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", contestFile, err)
+	}
+	return &contest, nil
+}
+
+// Info prints the metadata of the contest.
+func (c *Command) Info(ctx context.Context, w io.Writer) error {
+	contest, err := c.Contest(ctx)
+	if err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "Contest:\t%s\n", contest.ID)
+	fmt.Fprintf(tw, "Title:\t%s\n", contest.Title)
+	if !contest.StartAt.IsZero() {
+		fmt.Fprintf(tw, "Start:\t%s\n", contest.StartAt.Local().Format(time.DateTime+" MST"))
+		fmt.Fprintf(tw, "End:\t%s\n", contest.EndAt().Local().Format(time.DateTime+" MST"))
+		fmt.Fprintf(tw, "Duration:\t%s\n", contest.Duration)
+	}
+	fmt.Fprintf(tw, "Penalty:\t%s\n", contest.Penalty)
+	if contest.RatedRange != "" {
+		fmt.Fprintf(tw, "Rated Range:\t%s\n", contest.RatedRange)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	if len(contest.Tasks) > 0 {
+		fmt.Fprintln(w)
+		tw = tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "Task\tID\tTitle\tScore\tTime\tMemory")
+		for _, t := range contest.Tasks {
+			// init で保存したメタデータがない問題は ID だけ表示する
+			metadata, err := loadTaskMetadata(t.Index)
+			if err != nil {
+				fmt.Fprintf(tw, "%s\t%s\t-\t-\t-\t-\n", t.Index, t.ID)
+				continue
+			}
+			score, timeLimit, memoryLimit := "-", "-", "-"
+			if metadata.Score > 0 {
+				score = strconv.Itoa(metadata.Score)
+			}
+			if metadata.TimeLimitMS > 0 {
+				timeLimit = metadata.TimeLimit().String()
+			}
+			if metadata.MemoryLimitMB > 0 {
+				memoryLimit = fmt.Sprintf("%d MB", metadata.MemoryLimitMB)
+			}
+			fmt.Fprintf(
+				tw, "%s\t%s\t%s\t%s\t%s\t%s\n",
+				t.Index, t.ID, cmp.Or(metadata.Title, "-"), score, timeLimit, memoryLimit,
+			)
+		}
+		return tw.Flush()
+	}
+	return nil
+}
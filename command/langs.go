@@ -0,0 +1,164 @@
+package command
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"text/tabwriter"
+	"time"
+
+	"github.com/cry999/atcoder-cli/api"
+)
+
+// LanguageMapping maps a local language name to a language of the judge,
+// either by the ID or by a pattern of the name (e.g. "PyPy 3"). The ID takes
+// precedence.
+type LanguageMapping struct {
+	ID      string
+	Pattern string
+}
+
+// resolve returns the language of the mapping in the list.
+func (m LanguageMapping) resolve(languages api.Languages) (api.Language, bool) {
+	if m.ID != "" {
+		return languages.Lookup(m.ID)
+	}
+	if m.Pattern != "" {
+		return languages.Match(m.Pattern)
+	}
+	return api.Language{}, false
+}
+
+func (m LanguageMapping) String() string {
+	if m.ID != "" {
+		return "id " + m.ID
+	}
+	return fmt.Sprintf("pattern %q", m.Pattern)
+}
+
+type languageCache struct {
+	FetchedAt time.Time     `json:"fetched_at"`
+	Languages api.Languages `json:"languages"`
+}
+
+// languagesFile returns the cache of the languages of the contest, since
+// the languages differ between contests judged by different judges.
+func languagesFile(cacheDir, contest string) string {
+	return filepath.Join(cacheDir, contest+".json")
+}
+
+func loadLanguages(cacheFile string) (api.Languages, error) {
+	data, err := os.ReadFile(cacheFile)
+	if err != nil {
+		return nil, err
+	}
+	var cache languageCache
+	if err := json.Unmarshal(data, &cache); err != nil {
+		return nil, err
+	}
+	return cache.Languages, nil
+}
+
+func saveLanguages(cacheFile string, languages api.Languages) error {
+	data, err := json.MarshalIndent(languageCache{FetchedAt: time.Now(), Languages: languages}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(cacheFile), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(cacheFile, data, 0644)
+}
+
+// fetchLanguages fetches the languages and caches them.
+func fetchLanguages(ctx context.Context, client *api.Client, cacheFile string) (api.Languages, error) {
+	languages, err := client.FetchLanguages(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := saveLanguages(cacheFile, languages); err != nil {
+		slog.WarnContext(ctx, "failed to cache languages", slog.String("file", cacheFile), slog.String("err", err.Error()))
+	}
+	return languages, nil
+}
+
+// ResolveLanguage returns the ID of the language of the mapping in the
+// contest. The cached languages of the contest are used first, and fetched
+// again when the mapping does not resolve in them, e.g. after a language
+// update of the judge.
+func (c *Command) ResolveLanguage(ctx context.Context, cacheDir string, mapping LanguageMapping) (string, error) {
+	// ID だけなら言語一覧を取得せずにそのまま使う
+	if mapping.ID != "" {
+		return mapping.ID, nil
+	}
+
+	cacheFile := languagesFile(cacheDir, c.family.ContestName())
+
+	languages, err := loadLanguages(cacheFile)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		slog.WarnContext(ctx, "failed to load cached languages", slog.String("file", cacheFile), slog.String("err", err.Error()))
+	}
+	if lang, ok := mapping.resolve(languages); ok {
+		return lang.ID, nil
+	}
+
+	client := c.newClient()
+	defer client.Shutdown()
+
+	languages, err = fetchLanguages(ctx, client, cacheFile)
+	if err != nil {
+		return "", err
+	}
+	lang, ok := mapping.resolve(languages)
+	if !ok {
+		return "", fmt.Errorf("no language matches %s", mapping)
+	}
+	slog.InfoContext(ctx, "resolved language", slog.String("language", lang.String()), slog.String("id", lang.ID))
+	return lang.ID, nil
+}
+
+// ListLanguages fetches and prints the languages of the judge, and how the
+// mappings resolve. A warning is printed for a mapping which no longer
+// resolves.
+func ListLanguages(ctx context.Context, w io.Writer, cacheDir string, mappings map[string]LanguageMapping, opts ...api.ClientOption) error {
+	client := api.NewClient(nil, opts...)
+	defer client.Shutdown()
+
+	languages, err := fetchLanguages(ctx, client, languagesFile(cacheDir, api.LanguagesContest))
+	if err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tName\tVersion")
+	for _, l := range languages {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", l.ID, l.Name, l.Version)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	if len(mappings) == 0 {
+		return nil
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, styleTitle.Render("Configured"))
+	tw = tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, name := range slices.Sorted(maps.Keys(mappings)) {
+		mapping := mappings[name]
+		lang, ok := mapping.resolve(languages)
+		if !ok {
+			fmt.Fprintf(tw, "%s\t-\t%s no language matches %s\n", name, styleWA.Render("WARN"), mapping)
+			continue
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", name, lang.ID, lang)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,288 @@
+package command
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// toolsDir is where the local tools of a heuristic contest are unpacked.
+const toolsDir = "tools"
+
+var scorePattern = regexp.MustCompile(`(?i)score\s*=\s*(-?\d+)`)
+
+type ScoreOptions []ScoreOption
+
+type ScoreOption func(*scoreConfig)
+
+type scoreConfig struct {
+	seeds    int
+	jobs     int
+	build    []string
+	scorer   []string
+	minimize bool
+}
+
+// ScoreWithSeeds limits the evaluation to the first n seeds.
+func ScoreWithSeeds(n int) ScoreOption {
+	return func(sc *scoreConfig) {
+		sc.seeds = n
+	}
+}
+
+// ScoreWithJobs sets the number of seeds evaluated in parallel.
+func ScoreWithJobs(n int) ScoreOption {
+	return func(sc *scoreConfig) {
+		if n > 0 {
+			sc.jobs = n
+		}
+	}
+}
+
+// ScoreWithBuild sets the command building the local tools.
+func ScoreWithBuild(build []string) ScoreOption {
+	return func(sc *scoreConfig) {
+		sc.build = build
+	}
+}
+
+// ScoreWithScorer sets the command evaluating an output. "{input}" and
+// "{output}" in it are replaced with the file paths.
+func ScoreWithScorer(scorer []string) ScoreOption {
+	return func(sc *scoreConfig) {
+		sc.scorer = scorer
+	}
+}
+
+// ScoreWithMinimize treats a lower score as better.
+func ScoreWithMinimize() ScoreOption {
+	return func(sc *scoreConfig) {
+		sc.minimize = true
+	}
+}
+
+// scoreRun is the result of evaluating a solution over the seeds.
+type scoreRun struct {
+	Time   time.Time        `json:"time"`
+	Scores map[string]int64 `json:"scores"`
+}
+
+type seedResult struct {
+	seed  string
+	score int64
+	err   error
+}
+
+// RunScore evaluates the solution of the task over the input seeds of the
+// local tools, and compares the scores with the best previous run.
+func (c *Command) RunScore(ctx context.Context, taskIndex string, opts ...ScoreOption) error {
+	cfg := scoreConfig{jobs: runtime.NumCPU()}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if len(cfg.scorer) == 0 {
+		return errors.New("no scorer command is configured")
+	}
+
+	inputs, err := filepath.Glob(filepath.Join(toolsDir, "in", "*.txt"))
+	if err != nil {
+		return err
+	}
+	if len(inputs) == 0 {
+		return fmt.Errorf("no input seeds in %s; run init first", filepath.Join(toolsDir, "in"))
+	}
+	slices.Sort(inputs)
+	if cfg.seeds > 0 && cfg.seeds < len(inputs) {
+		inputs = inputs[:cfg.seeds]
+	}
+
+	if len(cfg.build) > 0 {
+		build := exec.CommandContext(ctx, cfg.build[0], cfg.build[1:]...)
+		build.Dir = toolsDir
+		build.Stdout = os.Stderr
+		build.Stderr = os.Stderr
+		if err := build.Run(); err != nil {
+			slog.ErrorContext(ctx, "failed to build local tools", slog.Any("command", cfg.build), slog.String("err", err.Error()))
+			return err
+		}
+	}
+
+	outDir := filepath.Join(taskIndex, "out")
+	if err := os.MkdirAll(outDir, 0755); err != nil {
+		return err
+	}
+
+	results := make([]seedResult, len(inputs))
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, cfg.jobs)
+	for i, input := range inputs {
+		wg.Go(func() {
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			seed := strings.TrimSuffix(filepath.Base(input), ".txt")
+			score, err := c.scoreSeed(ctx, taskIndex, cfg.scorer, input, filepath.Join(outDir, seed+".txt"))
+			results[i] = seedResult{seed: seed, score: score, err: err}
+		})
+	}
+	wg.Wait()
+
+	historyDir := filepath.Join(taskIndex, "scores")
+	bestFile := filepath.Join(historyDir, "best.json")
+	best, err := loadScoreRun(bestFile)
+	if err != nil {
+		slog.WarnContext(ctx, "failed to load best scores", slog.String("file", bestFile), slog.String("err", err.Error()))
+	}
+
+	run := scoreRun{Time: time.Now(), Scores: map[string]int64{}}
+	better := func(a, b int64) bool {
+		if cfg.minimize {
+			return a < b
+		}
+		return a > b
+	}
+
+	var (
+		total                  int64
+		commonTotal, bestTotal int64
+		common, failed         int
+	)
+	for _, r := range results {
+		if r.err != nil {
+			failed++
+			fmt.Printf("%s seed %s: %s\n", styleWA.Render("ERROR"), r.seed, r.err)
+			continue
+		}
+		run.Scores[r.seed] = r.score
+		total += r.score
+
+		prev, ok := best.Scores[r.seed]
+		if !ok {
+			fmt.Printf("%s seed %s: %d\n", styleTitle.Render("SCORE"), r.seed, r.score)
+			continue
+		}
+		common++
+		commonTotal += r.score
+		bestTotal += prev
+		diff := fmt.Sprintf("%+d", r.score-prev)
+		switch {
+		case better(r.score, prev):
+			diff = styleDiffPlus.Render(diff)
+		case better(prev, r.score):
+			diff = styleDiffMinus.Render(diff)
+		}
+		fmt.Printf("%s seed %s: %d (best %d, %s)\n", styleTitle.Render("SCORE"), r.seed, r.score, prev, diff)
+	}
+
+	fmt.Println()
+	if n := len(run.Scores); n > 0 {
+		fmt.Printf("%s %d seeds: total %d, mean %.2f\n", styleTitle.Render("Result"), n, total, float64(total)/float64(n))
+	}
+	if common > 0 {
+		fmt.Printf(
+			"%s %d seeds in common: total %d, best %d (run at %s)\n",
+			styleTitle.Render("Compare"), common, commonTotal, bestTotal, best.Time.Local().Format(time.DateTime),
+		)
+	}
+
+	if err := os.MkdirAll(historyDir, 0755); err != nil {
+		return err
+	}
+	if err := saveScoreRun(filepath.Join(historyDir, "last.json"), run); err != nil {
+		return err
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d seeds failed", failed, len(results))
+	}
+	// a run over a subset of the seeds of the best run does not replace it
+	if len(best.Scores) == 0 || (common == len(best.Scores) && better(commonTotal, bestTotal)) {
+		fmt.Printf("%s new best\n", styleAC.Render("BEST"))
+		return saveScoreRun(bestFile, run)
+	}
+	return nil
+}
+
+// scoreSeed runs the solution with input, writes its output to output and
+// evaluates it with the scorer.
+func (c *Command) scoreSeed(ctx context.Context, taskIndex string, scorer []string, input, output string) (int64, error) {
+	in, err := os.Open(input)
+	if err != nil {
+		return 0, err
+	}
+	defer in.Close()
+	out, err := os.Create(output)
+	if err != nil {
+		return 0, err
+	}
+	defer out.Close()
+
+	var errout bytes.Buffer
+	solution := solutionCommand(ctx, taskIndex)
+	solution.Stdin = in
+	solution.Stdout = out
+	solution.Stderr = &errout
+	if err := solution.Run(); err != nil {
+		return 0, fmt.Errorf("%s: %w", strings.TrimSpace(errout.String()), err)
+	}
+
+	absInput, err := filepath.Abs(input)
+	if err != nil {
+		return 0, err
+	}
+	absOutput, err := filepath.Abs(output)
+	if err != nil {
+		return 0, err
+	}
+	args := make([]string, len(scorer))
+	for i, a := range scorer {
+		a = strings.ReplaceAll(a, "{input}", absInput)
+		args[i] = strings.ReplaceAll(a, "{output}", absOutput)
+	}
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Dir = toolsDir
+	result, err := cmd.CombinedOutput()
+	if err != nil {
+		return 0, fmt.Errorf("scorer failed: %s: %w", strings.TrimSpace(string(result)), err)
+	}
+	m := scorePattern.FindSubmatch(result)
+	if m == nil {
+		return 0, fmt.Errorf("no score in scorer output: %s", strings.TrimSpace(string(result)))
+	}
+	return strconv.ParseInt(string(m[1]), 10, 64)
+}
+
+func loadScoreRun(path string) (scoreRun, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return scoreRun{}, nil
+	}
+	if err != nil {
+		return scoreRun{}, err
+	}
+	var run scoreRun
+	if err := json.Unmarshal(data, &run); err != nil {
+		return scoreRun{}, err
+	}
+	return run, nil
+}
+
+func saveScoreRun(path string, run scoreRun) error {
+	data, err := json.MarshalIndent(run, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
@@ -0,0 +1,22 @@
+package command
+
+import (
+	"context"
+	"os/exec"
+	"path/filepath"
+	"time"
+)
+
+// solutionFile returns the path of the solution of the task.
+func solutionFile(taskIndex string) string {
+	return filepath.Join(taskIndex, "main.py")
+}
+
+// solutionCommand returns the command running the solution of the task. The
+// solution is killed when ctx is done.
+func solutionCommand(ctx context.Context, taskIndex string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, "python3", solutionFile(taskIndex))
+	// 子プロセスが出力を開いたままでも待ち続けない
+	cmd.WaitDelay = time.Second
+	return cmd
+}
@@ -0,0 +1,116 @@
+package command
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/cry999/atcoder-cli/api"
+)
+
+type submissionsConfig struct {
+	taskIndex string
+	status    string
+	language  string
+	json      bool
+}
+
+type SubmissionsOptions []SubmissionsOption
+
+type SubmissionsOption func(*submissionsConfig)
+
+// SubmissionsWithTask lists only the submissions to the task.
+func SubmissionsWithTask(taskIndex string) SubmissionsOption {
+	return func(cfg *submissionsConfig) {
+		cfg.taskIndex = taskIndex
+	}
+}
+
+// SubmissionsWithStatus lists only the submissions with the verdict (e.g. "WA").
+func SubmissionsWithStatus(status string) SubmissionsOption {
+	return func(cfg *submissionsConfig) {
+		cfg.status = strings.ToUpper(status)
+	}
+}
+
+// SubmissionsWithLanguage lists only the submissions whose language contains
+// the name, ignoring case (e.g. "pypy").
+func SubmissionsWithLanguage(language string) SubmissionsOption {
+	return func(cfg *submissionsConfig) {
+		cfg.language = language
+	}
+}
+
+// SubmissionsWithJSON prints the submissions as JSON instead of a table.
+func SubmissionsWithJSON() SubmissionsOption {
+	return func(cfg *submissionsConfig) {
+		cfg.json = true
+	}
+}
+
+// ListSubmissions prints the submissions of the logged-in user to the contest.
+func (c *Command) ListSubmissions(ctx context.Context, w io.Writer, opts ...SubmissionsOption) error {
+	var cfg submissionsConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	client := c.newClient()
+	defer client.Shutdown()
+
+	filter := api.SubmissionFilter{Status: cfg.status}
+	if cfg.taskIndex != "" {
+		taskID, err := c.taskID(ctx, client, cfg.taskIndex)
+		if err != nil {
+			return err
+		}
+		filter.TaskID = taskID
+	}
+
+	submissions, err := client.FetchMySubmissions(ctx, filter)
+	if err != nil {
+		return err
+	}
+	if cfg.language != "" {
+		filtered := submissions[:0]
+		for _, s := range submissions {
+			if strings.Contains(strings.ToLower(s.Language), strings.ToLower(cfg.language)) {
+				filtered = append(filtered, s)
+			}
+		}
+		submissions = filtered
+	}
+
+	if cfg.json {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if submissions == nil {
+			submissions = []*api.Submission{}
+		}
+		return enc.Encode(submissions)
+	}
+
+	if len(submissions) == 0 {
+		fmt.Fprintln(w, "(none)")
+		return nil
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tSubmitted\tTask\tLanguage\tScore\tSize\tStatus\tTime\tMemory")
+	for _, s := range submissions {
+		execTime, memory := "-", "-"
+		if s.ExecTimeMS > 0 || s.MemoryKB > 0 {
+			execTime = fmt.Sprintf("%d ms", s.ExecTimeMS)
+			memory = fmt.Sprintf("%d KB", s.MemoryKB)
+		}
+		fmt.Fprintf(
+			tw, "%s\t%s\t%s\t%s\t%d\t%d B\t%s\t%s\t%s\n",
+			s.ID, s.SubmittedAt.Local().Format(time.DateTime), s.Task, s.Language,
+			s.Score, s.CodeLength, verdictStyle(s.Status).Render(s.Status), execTime, memory,
+		)
+	}
+	return tw.Flush()
+}
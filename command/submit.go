@@ -0,0 +1,51 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path"
+
+	"github.com/cry999/atcoder-cli/api"
+)
+
+// Submit submits the solution of the task in the language, prints the URL of
+// the submission and returns its ID.
+func (c *Command) Submit(ctx context.Context, w io.Writer, taskIndex, languageID string) (string, error) {
+	source, err := os.ReadFile(solutionFile(taskIndex))
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to read solution", slog.String("file", solutionFile(taskIndex)), slog.String("err", err.Error()))
+		return "", err
+	}
+
+	client := c.newClient()
+	defer client.Shutdown()
+
+	taskID, err := c.taskID(ctx, client, taskIndex)
+	if err != nil {
+		return "", err
+	}
+
+	submissionURL, err := client.Submit(ctx, taskID, languageID, string(source))
+	if err != nil {
+		return "", err
+	}
+	fmt.Fprintf(w, "%s submitted %s: %s\n", styleAC.Render("OK"), taskID, submissionURL)
+	return api.SubmissionID(submissionURL), nil
+}
+
+// taskID returns the task ID (e.g. "abc350_d") of the task index.
+func (c *Command) taskID(ctx context.Context, client *api.Client, taskIndex string) (string, error) {
+	tasks, err := c.taskList(ctx, client, true)
+	if err != nil {
+		return "", err
+	}
+	for _, t := range tasks {
+		if t.Index == taskIndex {
+			return path.Base(t.URL.Path), nil
+		}
+	}
+	return "", fmt.Errorf("no task %q in %s", taskIndex, c.family.ContestName())
+}
@@ -0,0 +1,175 @@
+package command
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/cry999/atcoder-cli/api"
+	"github.com/cry999/atcoder-cli/contests"
+)
+
+// taskListFile caches the task list of the contest in its working directory.
+const taskListFile = "tasks.json"
+
+type cachedTask struct {
+	Index string `json:"index"`
+	URL   string `json:"url"`
+}
+
+// taskList returns the task list of the contest. The local cache is used if
+// cached is true and it has tasks; otherwise the list is fetched and cached.
+func (c *Command) taskList(ctx context.Context, client *api.Client, cached bool) ([]*api.Task, error) {
+	if cached {
+		tasks, err := loadTaskList()
+		if err == nil && len(tasks) > 0 {
+			slog.InfoContext(ctx, "using cached task list", slog.String("file", taskListFile))
+			return tasks, nil
+		}
+		// 空の一覧は開始前に保存されたものなので取り直す
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			slog.WarnContext(ctx, "failed to load cached task list", slog.String("file", taskListFile), slog.String("err", err.Error()))
+		}
+	}
+
+	tasks, err := client.FetchTaskList(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// 開始前のコンテストでは一覧が空なので保存しない
+	if len(tasks) == 0 {
+		return tasks, nil
+	}
+	if err := saveTaskList(tasks); err != nil {
+		slog.WarnContext(ctx, "failed to cache task list", slog.String("file", taskListFile), slog.String("err", err.Error()))
+	}
+	return tasks, nil
+}
+
+// taskMetadataFile keeps the metadata of a task in its task directory.
+const taskMetadataFile = "metadata.json"
+
+func saveTaskMetadata(task *api.Task) error {
+	data, err := json.MarshalIndent(task.Metadata, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(task.Index, taskMetadataFile), data, 0644)
+}
+
+// loadTaskMetadata loads the metadata of the task saved by init.
+func loadTaskMetadata(taskIndex string) (*api.TaskMetadata, error) {
+	data, err := os.ReadFile(filepath.Join(taskIndex, taskMetadataFile))
+	if err != nil {
+		return nil, err
+	}
+	var metadata api.TaskMetadata
+	if err := json.Unmarshal(data, &metadata); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", taskMetadataFile, err)
+	}
+	return &metadata, nil
+}
+
+func loadTaskList() ([]*api.Task, error) {
+	data, err := os.ReadFile(taskListFile)
+	if err != nil {
+		return nil, err
+	}
+	var cache []cachedTask
+	if err := json.Unmarshal(data, &cache); err != nil {
+		return nil, err
+	}
+	tasks := make([]*api.Task, 0, len(cache))
+	for _, t := range cache {
+		u, err := url.Parse(t.URL)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, &api.Task{URL: u, Index: t.Index})
+	}
+	return tasks, nil
+}
+
+func saveTaskList(tasks []*api.Task) error {
+	cache := make([]cachedTask, len(tasks))
+	for i, t := range tasks {
+		cache[i] = cachedTask{Index: t.Index, URL: t.URL.String()}
+	}
+	data, err := json.MarshalIndent(cache, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(taskListFile, data, 0644)
+}
+
+// TaskIndex resolves a task index or task ID given by the user into the task
+// index used for the task directory.
+func (c *Command) TaskIndex(raw string) string {
+	if tasks, err := loadTaskList(); err == nil {
+		for _, t := range tasks {
+			if path.Base(t.URL.Path) == raw {
+				return t.Index
+			}
+		}
+	}
+	return contests.NormalizeTaskIndex(c.family, raw)
+}
+
+// selectTasks returns the tasks selected by spec, a comma separated list of
+// task indexes or ranges of them (e.g. "001-010,015"). Ranges follow the
+// order of the task list, and a task may also be given by its task ID (e.g.
+// "abc350_d"). An empty spec selects all tasks.
+func selectTasks(family contests.Family, tasks []*api.Task, spec string) ([]*api.Task, error) {
+	if spec == "" {
+		return tasks, nil
+	}
+
+	position := func(raw string) (int, error) {
+		raw = strings.TrimSpace(raw)
+		index := contests.NormalizeTaskIndex(family, raw)
+		i := slices.IndexFunc(tasks, func(t *api.Task) bool {
+			return t.Index == index || path.Base(t.URL.Path) == raw
+		})
+		if i < 0 {
+			return 0, fmt.Errorf("no task %q in %s", index, family.ContestName())
+		}
+		return i, nil
+	}
+
+	selected := make([]bool, len(tasks))
+	for item := range strings.SplitSeq(spec, ",") {
+		from, to, isRange := strings.Cut(item, "-")
+		first, err := position(from)
+		if err != nil {
+			return nil, err
+		}
+		last := first
+		if isRange {
+			if last, err = position(to); err != nil {
+				return nil, err
+			}
+		}
+		if first > last {
+			return nil, fmt.Errorf("invalid task range %q", item)
+		}
+		for i := first; i <= last; i++ {
+			selected[i] = true
+		}
+	}
+
+	var result []*api.Task
+	for i, task := range tasks {
+		if selected[i] {
+			result = append(result, task)
+		}
+	}
+	return result, nil
+}
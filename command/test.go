@@ -3,13 +3,14 @@ package command
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"io/fs"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/google/go-cmp/cmp"
@@ -70,6 +71,11 @@ func TestWithVerbose() TestOption {
 	}
 }
 
+// timeLimitFactor is how many times the time limit a solution may run in
+// tests before it is killed as TLE. A solution slower than the time limit but
+// finishing within it is still reported as TLE.
+const timeLimitFactor = 2
+
 func (c *Command) RunTest(ctx context.Context, taskIndex string, opts ...TestOption) error {
 	// TODO: language
 
@@ -80,6 +86,12 @@ func (c *Command) RunTest(ctx context.Context, taskIndex string, opts ...TestOpt
 		opt(&cfg)
 	}
 
+	// the time limit is known once init has saved the metadata of the task
+	var timeLimit time.Duration
+	if metadata, err := loadTaskMetadata(taskIndex); err == nil {
+		timeLimit = metadata.TimeLimit()
+	}
+
 	type sample struct {
 		input, output string
 	}
@@ -117,19 +129,39 @@ func (c *Command) RunTest(ctx context.Context, taskIndex string, opts ...TestOpt
 		}
 		defer inputFile.Close()
 
-		execfile := fmt.Sprintf("%s/main.py", taskIndex)
+		execfile := solutionFile(taskIndex)
 		if _, err := os.Stat(execfile); err != nil && os.IsNotExist(err) {
 			fmt.Println(styleWA.Render("Error:"))
 			fmt.Println("No such file:", execfile)
 		}
 
-		var input, output, errout bytes.Buffer
-		python3 := exec.CommandContext(ctx, "python3", execfile)
-		python3.Stdin = io.TeeReader(inputFile, &input)
-		python3.Stdout = &output
-		python3.Stderr = &errout
+		// 無限ループなどで止まらない解答は制限時間の数倍で打ち切る
+		runCtx, cancelRun := ctx, context.CancelFunc(func() {})
+		if timeLimit > 0 {
+			runCtx, cancelRun = context.WithTimeout(ctx, timeLimit*timeLimitFactor)
+		}
 
-		if err := python3.Run(); err != nil {
+		var input, output, errout bytes.Buffer
+		solution := solutionCommand(runCtx, taskIndex)
+		solution.Stdin = io.TeeReader(inputFile, &input)
+		solution.Stdout = &output
+		solution.Stderr = &errout
+
+		start := time.Now()
+		err = solution.Run()
+		elapsed := time.Since(start)
+		killed := errors.Is(runCtx.Err(), context.DeadlineExceeded)
+		cancelRun()
+		if killed {
+			fmt.Printf("%s Test case %s (killed after %d ms / %s)\n", styleWA.Render("TLE"), number, elapsed.Milliseconds(), timeLimit)
+			if cfg.verbose {
+				fmt.Printf("%s:\n%s\n", styleTitle.Render("Input"), input.String())
+				fmt.Printf("%s:\n%s\n", styleTitle.Render("Debug"), errout.String())
+			}
+			fmt.Println()
+			continue
+		}
+		if err != nil {
 			fmt.Printf("%s: Test case %s:\n", styleWA.Render("ERROR"), number)
 			fmt.Println(styleTitle.Render("Input:"))
 			fmt.Println(input.String())
@@ -144,18 +176,26 @@ func (c *Command) RunTest(ctx context.Context, taskIndex string, opts ...TestOpt
 		if err != nil {
 			return err
 		}
+		// samples are kept as in the statement, which may lack the final newline
 		diff := cmp.Diff(
-			strings.Split(string(expect), "\n"),
-			strings.Split(output.String(), "\n"),
+			strings.Split(strings.TrimRight(string(expect), "\n"), "\n"),
+			strings.Split(strings.TrimRight(output.String(), "\n"), "\n"),
 		)
 
 		var result string
-		if diff == "" {
+		switch {
+		case timeLimit > 0 && elapsed > timeLimit:
+			result = styleWA.Render("TLE")
+		case diff != "":
+			result = styleWA.Render("WA")
+		default:
 			result = styleAC.Render("AC")
+		}
+		if timeLimit > 0 {
+			fmt.Printf("%s Test case %s (%d ms / %s)\n", result, number, elapsed.Milliseconds(), timeLimit)
 		} else {
-			result = styleWA.Render("WA")
+			fmt.Printf("%s Test case %s (%d ms)\n", result, number, elapsed.Milliseconds())
 		}
-		fmt.Printf("%s Test case %s\n", result, number)
 		if diff != "" || cfg.verbose {
 			fmt.Printf("%s:\n%s\n", styleTitle.Render("Input"), input.String())
 			fmt.Printf("%s:\n%s\n", styleTitle.Render("Debug"), errout.String())
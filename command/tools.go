@@ -0,0 +1,87 @@
+package command
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+)
+
+// FetchTools downloads the local tools archive of a heuristic contest and
+// unpacks it into the working directory.
+func (c *Command) FetchTools(ctx context.Context) error {
+	client := c.newClient()
+	defer client.Shutdown()
+
+	tasks, err := c.taskList(ctx, client, true)
+	if err != nil {
+		return err
+	}
+	if len(tasks) == 0 {
+		return fmt.Errorf("no tasks in %s", c.family.ContestName())
+	}
+
+	toolsURL, err := client.FetchLocalToolsURL(ctx, tasks[0])
+	if err != nil {
+		return err
+	}
+	archive, err := client.Download(ctx, toolsURL)
+	if err != nil {
+		return err
+	}
+	if err := unzip(archive, "."); err != nil {
+		slog.ErrorContext(ctx, "failed to unpack local tools", slog.String("url", toolsURL.String()), slog.String("err", err.Error()))
+		return err
+	}
+	slog.InfoContext(ctx, "unpacked local tools", slog.String("url", toolsURL.String()))
+	return nil
+}
+
+// unzip extracts the zip archive into dir.
+func unzip(archive []byte, dir string) error {
+	r, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	if err != nil {
+		return err
+	}
+	for _, f := range r.File {
+		if !filepath.IsLocal(f.Name) {
+			return fmt.Errorf("invalid file path in archive: %s", f.Name)
+		}
+		path := filepath.Join(dir, f.Name)
+		if f.FileInfo().IsDir() {
+			if err := os.MkdirAll(path, 0755); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+		if err := extractFile(f, path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func extractFile(f *zip.File, path string) error {
+	src, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, f.Mode().Perm()|0600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		dst.Close()
+		return err
+	}
+	return dst.Close()
+}
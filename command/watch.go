@@ -0,0 +1,90 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"text/tabwriter"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/cry999/atcoder-cli/api"
+)
+
+// Polling intervals of the judge status.
+const (
+	watchInitialInterval = time.Second
+	watchMaxInterval     = 10 * time.Second
+)
+
+// Watch polls the judge status of the submission until it is final, and
+// prints the progress, the verdict and the per-testcase results. It reports
+// whether the submission is accepted.
+func (c *Command) Watch(ctx context.Context, w io.Writer, submissionID string) (bool, error) {
+	client := c.newClient()
+	defer client.Shutdown()
+
+	interval := watchInitialInterval
+	var last string
+	for {
+		status, err := client.FetchSubmissionStatus(ctx, submissionID)
+		if err != nil {
+			return false, err
+		}
+		if status.Final() {
+			fmt.Fprintf(
+				w, "%s submission %s: score %s, %s, %s\n",
+				verdictStyle(status.Status).Render(status.Status), submissionID, status.Score, status.ExecTime, status.Memory,
+			)
+			results, err := client.FetchTestcaseResults(ctx, submissionID)
+			if err != nil {
+				slog.WarnContext(ctx, "failed to fetch testcase results", slog.String("submission", submissionID), slog.String("err", err.Error()))
+			}
+			printTestcaseResults(w, results)
+			return status.Status == "AC", nil
+		}
+
+		progress := status.Status
+		if status.Total > 0 {
+			progress = fmt.Sprintf("%d/%d %s", status.Judged, status.Total, status.Status)
+		}
+		if progress != last {
+			fmt.Fprintf(w, "%s %s\n", styleSkip.Render("WJ"), progress)
+			last = progress
+		} else {
+			// 進捗がなければ間隔を伸ばす
+			interval = min(interval*3/2, watchMaxInterval)
+		}
+
+		select {
+		case <-time.After(interval):
+		case <-ctx.Done():
+			return false, ctx.Err()
+		}
+	}
+}
+
+func printTestcaseResults(w io.Writer, results []api.TestcaseResult) {
+	if len(results) == 0 {
+		return
+	}
+	fmt.Fprintln(w)
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	defer tw.Flush()
+	fmt.Fprintln(tw, "Case\tStatus\tTime\tMemory")
+	for _, r := range results {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", r.Name, verdictStyle(r.Status).Render(r.Status), r.ExecTime, r.Memory)
+	}
+}
+
+func verdictStyle(verdict string) lipgloss.Style {
+	switch verdict {
+	case "AC":
+		return styleAC
+	case "WJ", "WR", "Judging":
+		return styleSkip
+	default:
+		return styleWA
+	}
+}
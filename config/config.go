@@ -2,19 +2,78 @@ package config
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"log/slog"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/BurntSushi/toml"
 	"github.com/cry999/atcoder-cli/contests/adt"
+	"github.com/cry999/atcoder-cli/contests/ahc"
 )
 
 // Config represents the configuration for the CLI tool.
 type Config struct {
 	WorkDir string     `toml:"workdir"`
 	ADT     adt.Config `toml:"adt"`
+	AHC     ahc.Config `toml:"ahc"`
+	Login   Login      `toml:"login"`
+	HTTP    HTTP       `toml:"http"`
+	// Language is the language of the solutions, a key of Languages.
+	Language  string              `toml:"language"`
+	Languages map[string]Language `toml:"languages"`
+}
+
+// Language represents the configuration of a language used for solutions.
+type Language struct {
+	// ID is the AtCoder language ID used for submissions. It takes precedence
+	// over Pattern.
+	ID string `toml:"id,omitempty"`
+	// Pattern finds the language by its name on the submit page, ignoring
+	// case (e.g. "PyPy 3"), so that it survives language updates.
+	Pattern string `toml:"pattern,omitempty"`
+}
+
+// defaultLanguages are merged into the configured languages.
+var defaultLanguages = map[string]Language{
+	"python": {Pattern: "Python (CPython 3"},
+	"cpp":    {Pattern: "C++ 20 (gcc"},
+	"rust":   {Pattern: "Rust (rustc"},
+}
+
+// Login represents the configuration for logging in to AtCoder.
+type Login struct {
+	// Username is used when no credential source provides one.
+	Username string `toml:"username,omitempty"`
+	// CredentialHelper is a command printing "username=..." and
+	// "password=..." lines (e.g. ["pass", "show", "atcoder"]). A line without
+	// "=" is taken as the password.
+	CredentialHelper []string `toml:"credential_helper,omitempty"`
+}
+
+// HTTP represents the configuration of the HTTP client.
+type HTTP struct {
+	// BaseURL is the URL of the site, https://atcoder.jp/ by default.
+	BaseURL   string `toml:"base_url,omitempty"`
+	UserAgent string `toml:"user_agent,omitempty"`
+	// Proxy is the URL of the proxy. The environment (HTTPS_PROXY etc.) is
+	// used by default.
+	Proxy string `toml:"proxy,omitempty"`
+	// Timeout limits the time of a request including reading the body. No
+	// timeout by default.
+	Timeout time.Duration `toml:"timeout,omitempty"`
+	// RetryBudget limits the time spent retrying a failed request (e.g. 429
+	// or 503 at the start of a contest), 30s by default.
+	RetryBudget time.Duration `toml:"retry_budget,omitempty"`
+	// CacheMaxAge is how long a cached task page is used without asking the
+	// server when the server cannot tell whether it changed, 1h by default.
+	CacheMaxAge time.Duration `toml:"cache_max_age,omitempty"`
+	// Rate limits the requests per second on average, and Burst is the
+	// number of requests allowed at once; 10 and 4 by default.
+	Rate  float64 `toml:"rate,omitempty"`
+	Burst int     `toml:"burst,omitempty"`
 }
 
 // LoadConfig loads the configuration from the specified file path.
@@ -54,10 +113,87 @@ func LoadConfig(ctx context.Context) (*Config, error) {
 	if config.ADT.DefaultLevel == "" {
 		config.ADT.DefaultLevel = adt.LevelAll
 	}
+	if config.Language == "" {
+		config.Language = "python"
+	}
+	if config.Languages == nil {
+		config.Languages = map[string]Language{}
+	}
+	for name, lang := range defaultLanguages {
+		if _, ok := config.Languages[name]; !ok {
+			config.Languages[name] = lang
+		}
+	}
+	defaultAHC := ahc.DefaultConfig()
+	if config.AHC.Build == nil {
+		config.AHC.Build = defaultAHC.Build
+	}
+	if len(config.AHC.Scorer) == 0 {
+		config.AHC.Scorer = defaultAHC.Scorer
+	}
+	if config.AHC.Objective == "" {
+		config.AHC.Objective = defaultAHC.Objective
+	}
+	if err := config.AHC.Validate(); err != nil {
+		slog.ErrorContext(ctx, "invalid ahc config", slog.String("file", configFilePath), slog.String("err", err.Error()))
+		return nil, fmt.Errorf("%s: ahc: %w", configFilePath, err)
+	}
 
 	return &config, nil
 }
 
+// StateDir returns the directory keeping the state of the CLI tool, such as
+// the login session.
+func StateDir() (string, error) {
+	stateHome := os.Getenv("XDG_STATE_HOME")
+	if stateHome == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		stateHome = filepath.Join(home, ".local", "state")
+	}
+	return filepath.Join(stateHome, "atcoder-cli"), nil
+}
+
+// SessionFile returns the path of the saved login session.
+func SessionFile() (string, error) {
+	dir, err := StateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "session.json"), nil
+}
+
+// CacheDir returns the directory keeping the data cached by the CLI tool,
+// such as the language list of the judge.
+func CacheDir() (string, error) {
+	cacheHome, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(cacheHome, "atcoder-cli"), nil
+}
+
+// HTTPCacheDir returns the directory of the cached pages.
+func HTTPCacheDir() (string, error) {
+	dir, err := CacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "http"), nil
+}
+
+// LanguagesDir returns the directory of the cached language lists, one for
+// each contest.
+func LanguagesDir() (string, error) {
+	dir, err := CacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "languages"), nil
+}
+
 // Dump writes the configuration to the provided writer in TOML format.
 func (c *Config) Dump(w io.Writer) error {
 	enc := toml.NewEncoder(os.Stdout)
@@ -0,0 +1,61 @@
+package abc
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/cry999/atcoder-cli/contests"
+)
+
+// ABC233 から ABC318 までは最後の問題が H ではなく Ex と表記されている。
+const (
+	firstExContest = 233
+	lastExContest  = 318
+)
+
+func init() {
+	contests.Register(contests.Registration{
+		Name:  "abc",
+		Usage: "<number> [task]",
+		Help:  "AtCoder Beginner Contest",
+		Parse: func(args []string) (contests.Family, error) {
+			return New(contests.Arg(args, 0))
+		},
+		TaskIndexArg: contests.ArgAt(1),
+	})
+}
+
+// New creates a new ABC contest family from the contest number (e.g. "350").
+func New(rawNumber string) (contests.Family, error) {
+	number, err := contests.ParseContestNumber("abc", rawNumber)
+	if err != nil {
+		return nil, err
+	}
+	return &family{number: number}, nil
+}
+
+type family struct {
+	number int
+}
+
+func (f *family) ContestName() string {
+	return fmt.Sprintf("abc%03d", f.number)
+}
+
+func (f *family) BaseDir(workdir string) string {
+	return filepath.Join(workdir, "abc", fmt.Sprintf("%03d", f.number))
+}
+
+// NormalizeTaskIndex maps "a".."g" to "A".."G", and "h" or "ex" to the
+// index used by the contest ("Ex" or "H").
+func (f *family) NormalizeTaskIndex(index string) string {
+	switch strings.ToLower(index) {
+	case "h", "ex":
+		if firstExContest <= f.number && f.number <= lastExContest {
+			return "Ex"
+		}
+		return "H"
+	}
+	return strings.ToUpper(index)
+}
@@ -1,6 +1,27 @@
 package adt
 
+import "fmt"
+
 // Config represents the configuration specific to AtCoder Daily Training contests.
 type Config struct {
 	DefaultLevel Level `toml:"default_level"`
+	// Schedules override the built-in timetable for the dates they cover.
+	Schedules []Schedule `toml:"schedule,omitempty"`
+}
+
+var config = Config{DefaultLevel: LevelAll}
+
+// Configure sets the configuration used when the family is selected on the
+// command line.
+func Configure(cfg Config) error {
+	if _, err := ParseLevels(string(cfg.DefaultLevel)); err != nil {
+		return fmt.Errorf("invalid default_level: %w", err)
+	}
+	configured, err := compileSchedules(cfg.Schedules)
+	if err != nil {
+		return err
+	}
+	config = cfg
+	timetables = append(configured, mustCompile(defaultSchedules)...)
+	return nil
 }
@@ -1,28 +1,78 @@
 package adt
 
 import (
-	"errors"
+	"flag"
 	"fmt"
 	"path/filepath"
+	"strings"
 	"time"
+
+	"github.com/cry999/atcoder-cli/contests"
 )
 
-// dailyHolds is dailyHolds[weekday][time] = number in the day
-var dailyHolds = map[time.Weekday]map[string]int{
-	time.Tuesday:   {"1530": 1, "1730": 2, "1930": 3},
-	time.Wednesday: {"1600": 1, "1800": 2, "2000": 3},
-	time.Thursday:  {"1630": 1, "1830": 2, "2030": 3},
+var flagLevel string
+
+func init() {
+	contests.Register(contests.Registration{
+		Name:  "adt",
+		Usage: "(<date:YYYYMMDD> <time:HHMM> | now | next | latest) [task]",
+		Help:  "AtCoder Daily Training (level is given by -adt-level)",
+		Flags: func(fs *flag.FlagSet) {
+			fs.StringVar(&flagLevel, "adt-level", string(config.DefaultLevel), "Level for ADT problems (easy, medium, hard, all), or a comma separated list of them")
+			fs.StringVar(&flagLevel, "level", string(config.DefaultLevel), "Alias of -adt-level")
+		},
+		Parse: func(args []string) (contests.Family, error) {
+			return parse(args, flagLevel, time.Now())
+		},
+		TaskIndexArg: func(args []string) int {
+			if IsSelector(contests.Arg(args, 0)) {
+				return 1
+			}
+			return 2
+		},
+	})
 }
-var dailyTimes = map[time.Weekday]map[int]string{
-	time.Tuesday:   {1: "1530", 2: "1730", 3: "1930"},
-	time.Wednesday: {1: "1600", 2: "1800", 3: "2000"},
-	time.Thursday:  {1: "1630", 2: "1830", 3: "2030"},
+
+// parse creates the family from the command line arguments. Several levels
+// make a group whose members are placed in per-level subdirectories.
+func parse(args []string, rawLevels string, now time.Time) (contests.Family, error) {
+	levels, err := ParseLevels(rawLevels)
+	if err != nil {
+		return nil, err
+	}
+
+	newFamily := func(level Level) (*Family, error) {
+		if IsSelector(contests.Arg(args, 0)) {
+			return NewFromSelector(contests.Arg(args, 0), now, string(level))
+		}
+		return New(contests.Arg(args, 0), contests.Arg(args, 1), string(level))
+	}
+
+	if len(levels) == 1 {
+		f, err := newFamily(levels[0])
+		if err != nil {
+			return nil, err
+		}
+		return f, nil
+	}
+	g := &group{}
+	for _, level := range levels {
+		f, err := newFamily(level)
+		if err != nil {
+			return nil, err
+		}
+		f.nested = true
+		g.members = append(g.members, f)
+	}
+	return g, nil
 }
 
 type Family struct {
 	date   time.Time
 	number int
 	level  Level
+	// nested places the tasks in the level subdirectory of the session.
+	nested bool
 }
 
 func New(rawDate, rawTime, rawLevel string) (*Family, error) {
@@ -30,30 +80,85 @@ func New(rawDate, rawTime, rawLevel string) (*Family, error) {
 	if err != nil {
 		return nil, err
 	}
-	timeToNumber, ok := dailyHolds[date.Weekday()]
-	if !ok {
-		return nil, errors.New("no ADT held on this date")
+	s, err := findSession(date, rawTime)
+	if err != nil {
+		return nil, err
 	}
-	number, ok := timeToNumber[rawTime]
-	if !ok {
-		return nil, errors.New("no ADT held at this time on this date")
+	level, err := ParseLevel(rawLevel)
+	if err != nil {
+		return nil, err
 	}
+	return &Family{date: date, number: s.number, level: level}, nil
+}
 
-	// TODO: validate rawLevel
-	return &Family{date: date, number: number, level: Level(rawLevel)}, nil
+// NewFromSelector creates a new ADT family for the session the relative
+// selector ("now", "next" or "latest") refers to at now.
+func NewFromSelector(selector string, now time.Time, rawLevel string) (*Family, error) {
+	s, err := resolveSelector(selector, now)
+	if err != nil {
+		return nil, err
+	}
+	level, err := ParseLevel(rawLevel)
+	if err != nil {
+		return nil, err
+	}
+	return &Family{date: s.date, number: s.number, level: level}, nil
 }
 
 func (f *Family) ContestName() string {
 	return fmt.Sprintf("adt_%s_%s_%d", f.level, f.date.Format("20060102"), f.number)
 }
 
+// BaseDir returns the directory of the session, or its level subdirectory
+// for a member of a group, so that several levels initialised together are
+// placed side by side.
 func (f *Family) BaseDir(workdir string) string {
+	if f.nested {
+		return filepath.Join(f.sessionDir(workdir), string(f.level))
+	}
+	return f.sessionDir(workdir)
+}
+
+func (f *Family) sessionDir(workdir string) string {
+	// the session always exists as the family is created from it
+	s, _ := sessionAt(f.date, f.number)
 	return filepath.Join(
 		workdir,
 		"adt",
 		fmt.Sprintf("%04d", f.date.Year()),
 		fmt.Sprintf("%02d", f.date.Month()),
 		fmt.Sprintf("%02d", f.date.Day()),
-		dailyTimes[f.date.Weekday()][f.number],
+		s.clock,
 	)
 }
+
+// group is several levels of the same session.
+type group struct {
+	members []*Family
+}
+
+func (g *group) ContestName() string {
+	names := make([]string, len(g.members))
+	for i, m := range g.members {
+		names[i] = m.ContestName()
+	}
+	return strings.Join(names, ",")
+}
+
+func (g *group) BaseDir(workdir string) string {
+	return g.members[0].sessionDir(workdir)
+}
+
+func (g *group) Members() []contests.Family {
+	members := make([]contests.Family, len(g.members))
+	for i, m := range g.members {
+		members[i] = m
+	}
+	return members
+}
+
+// Schedule returns the start time and the duration of the session.
+func (f *Family) Schedule() (time.Time, time.Duration) {
+	s, _ := sessionAt(f.date, f.number)
+	return s.Start(), s.length
+}
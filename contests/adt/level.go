@@ -1,5 +1,11 @@
 package adt
 
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
 // Level represents the difficulty level for ADT problems.
 type Level string
 
@@ -10,3 +16,34 @@ var (
 	LevelHard   Level = "hard"
 	LevelAll    Level = "all"
 )
+
+// Levels lists all valid levels.
+var Levels = []Level{LevelEasy, LevelMedium, LevelHard, LevelAll}
+
+// ParseLevel validates rawLevel as a Level.
+func ParseLevel(rawLevel string) (Level, error) {
+	level := Level(strings.ToLower(strings.TrimSpace(rawLevel)))
+	if !slices.Contains(Levels, level) {
+		valid := make([]string, len(Levels))
+		for i, l := range Levels {
+			valid[i] = string(l)
+		}
+		return "", fmt.Errorf("unknown ADT level %q (expected one of %s)", rawLevel, strings.Join(valid, ", "))
+	}
+	return level, nil
+}
+
+// ParseLevels parses a comma separated list of levels (e.g. "easy,hard").
+func ParseLevels(rawLevels string) ([]Level, error) {
+	var levels []Level
+	for rawLevel := range strings.SplitSeq(rawLevels, ",") {
+		level, err := ParseLevel(rawLevel)
+		if err != nil {
+			return nil, err
+		}
+		if !slices.Contains(levels, level) {
+			levels = append(levels, level)
+		}
+	}
+	return levels, nil
+}
@@ -0,0 +1,149 @@
+package adt
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Schedule is the ADT timetable effective in a period of dates.
+type Schedule struct {
+	// From and Until bound the period (inclusive, "20060102"). An empty
+	// value leaves the period open on that side.
+	From  string `toml:"from,omitempty"`
+	Until string `toml:"until,omitempty"`
+	// Duration is the length of each session.
+	Duration time.Duration `toml:"duration"`
+	// Slots maps a weekday name (e.g. "tuesday") to the start times of the
+	// sessions held on the day ("HHMM"), in order.
+	Slots map[string][]string `toml:"slots"`
+}
+
+// defaultSchedules is the built-in ADT timetable, used for the dates no
+// configured schedule covers.
+var defaultSchedules = []Schedule{
+	{
+		Duration: time.Hour,
+		Slots: map[string][]string{
+			"tuesday":   {"1530", "1730", "1930"},
+			"wednesday": {"1600", "1800", "2000"},
+			"thursday":  {"1630", "1830", "2030"},
+		},
+	},
+}
+
+// timetable is a validated Schedule.
+type timetable struct {
+	from, until time.Time
+	duration    time.Duration
+	slots       map[time.Weekday][]string
+}
+
+// timetables are looked up in order, so configured ones take precedence over
+// the built-in ones.
+var timetables = mustCompile(defaultSchedules)
+
+func mustCompile(schedules []Schedule) []timetable {
+	tts, err := compileSchedules(schedules)
+	if err != nil {
+		panic(err)
+	}
+	return tts
+}
+
+func compileSchedules(schedules []Schedule) ([]timetable, error) {
+	tts := make([]timetable, 0, len(schedules))
+	for i, s := range schedules {
+		tt, err := s.compile()
+		if err != nil {
+			return nil, fmt.Errorf("invalid ADT schedule #%d: %w", i+1, err)
+		}
+		tts = append(tts, tt)
+	}
+	return tts, nil
+}
+
+func (s Schedule) compile() (timetable, error) {
+	tt := timetable{duration: s.Duration, slots: map[time.Weekday][]string{}}
+	if tt.duration <= 0 {
+		return timetable{}, fmt.Errorf("duration must be positive: %s", s.Duration)
+	}
+
+	var err error
+	if s.From != "" {
+		if tt.from, err = time.Parse("20060102", s.From); err != nil {
+			return timetable{}, fmt.Errorf("invalid from: %w", err)
+		}
+	}
+	if s.Until != "" {
+		if tt.until, err = time.Parse("20060102", s.Until); err != nil {
+			return timetable{}, fmt.Errorf("invalid until: %w", err)
+		}
+		if !tt.from.IsZero() && tt.until.Before(tt.from) {
+			return timetable{}, fmt.Errorf("until %s is before from %s", s.Until, s.From)
+		}
+	}
+
+	for name, clocks := range s.Slots {
+		weekday, ok := parseWeekday(name)
+		if !ok {
+			return timetable{}, fmt.Errorf("unknown weekday %q", name)
+		}
+		for _, clock := range clocks {
+			if _, err := time.Parse("1504", clock); err != nil || len(clock) != 4 {
+				return timetable{}, fmt.Errorf("invalid time %q on %s (expected HHMM)", clock, name)
+			}
+		}
+		tt.slots[weekday] = slices.Clone(clocks)
+		slices.Sort(tt.slots[weekday])
+	}
+	return tt, nil
+}
+
+func parseWeekday(name string) (time.Weekday, bool) {
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		if strings.EqualFold(name, d.String()) || strings.EqualFold(name, d.String()[:3]) {
+			return d, true
+		}
+	}
+	return 0, false
+}
+
+// covers reports whether the timetable is effective on date.
+func (tt timetable) covers(date time.Time) bool {
+	if !tt.from.IsZero() && date.Before(tt.from) {
+		return false
+	}
+	if !tt.until.IsZero() && date.After(tt.until) {
+		return false
+	}
+	return true
+}
+
+// timetableOn returns the timetable effective on date.
+func timetableOn(date time.Time) (timetable, bool) {
+	for _, tt := range timetables {
+		if tt.covers(date) {
+			return tt, true
+		}
+	}
+	return timetable{}, false
+}
+
+// Upcoming returns the next n sessions which have not ended at now.
+func Upcoming(now time.Time, n int) []Session {
+	var sessions []Session
+	if s, ok := latestSession(now); ok && now.Before(s.End()) {
+		sessions = append(sessions, s)
+	}
+	for len(sessions) < n {
+		s, ok := nextSession(now)
+		if !ok {
+			break
+		}
+		sessions = append(sessions, s)
+		now = s.Start()
+	}
+	return sessions
+}
@@ -0,0 +1,177 @@
+package adt
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ADT is scheduled in JST regardless of the local timezone.
+var jst = time.FixedZone("JST", 9*60*60)
+
+// searchDays limits how far relative selectors look for a session.
+const searchDays = 14
+
+// Relative selectors accepted instead of an explicit date and time.
+const (
+	SelectorNow    = "now"
+	SelectorNext   = "next"
+	SelectorLatest = "latest"
+)
+
+// IsSelector reports whether s is a relative selector.
+func IsSelector(s string) bool {
+	switch s {
+	case SelectorNow, SelectorNext, SelectorLatest:
+		return true
+	}
+	return false
+}
+
+// Session is a single ADT slot, identified by its date and number in the day.
+type Session struct {
+	date   time.Time
+	number int
+	clock  string
+	length time.Duration
+}
+
+// Number returns the number of the session in the day, starting from 1.
+func (s Session) Number() int {
+	return s.number
+}
+
+// Start returns the start time of the session in JST.
+func (s Session) Start() time.Time {
+	t, _ := time.Parse("1504", s.clock)
+	return time.Date(s.date.Year(), s.date.Month(), s.date.Day(), t.Hour(), t.Minute(), 0, 0, jst)
+}
+
+// End returns the end time of the session in JST.
+func (s Session) End() time.Time {
+	return s.Start().Add(s.length)
+}
+
+// String formats the session as the arguments accepted by New.
+func (s Session) String() string {
+	return s.date.Format("20060102") + " " + s.clock
+}
+
+// sessionsOn returns the sessions held on the date in the order of the day.
+func sessionsOn(date time.Time) []Session {
+	tt, ok := timetableOn(date)
+	if !ok {
+		return nil
+	}
+	clocks := tt.slots[date.Weekday()]
+	sessions := make([]Session, 0, len(clocks))
+	for i, clock := range clocks {
+		sessions = append(sessions, Session{date: date, number: i + 1, clock: clock, length: tt.duration})
+	}
+	return sessions
+}
+
+// findSession returns the session held at clock ("HHMM") on the date.
+func findSession(date time.Time, clock string) (Session, error) {
+	sessions := sessionsOn(date)
+	if len(sessions) == 0 {
+		return Session{}, fmt.Errorf(
+			"no ADT held on %s; %s",
+			date.Format("20060102"),
+			nearestSessions(time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, jst)),
+		)
+	}
+	held := make([]string, 0, len(sessions))
+	for _, s := range sessions {
+		if s.clock == clock {
+			return s, nil
+		}
+		held = append(held, s.String())
+	}
+	return Session{}, fmt.Errorf(
+		"no ADT held at %q on %s; sessions on the date are %s",
+		clock, date.Format("20060102"), strings.Join(held, ", "),
+	)
+}
+
+// sessionAt returns the number-th session on the date.
+func sessionAt(date time.Time, number int) (Session, bool) {
+	sessions := sessionsOn(date)
+	if number < 1 || number > len(sessions) {
+		return Session{}, false
+	}
+	return sessions[number-1], true
+}
+
+// dateOf returns the JST calendar date of t as the midnight in UTC, which is
+// how dates given on the command line are represented.
+func dateOf(t time.Time) time.Time {
+	t = t.In(jst)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// latestSession returns the last session started at or before t.
+func latestSession(t time.Time) (Session, bool) {
+	date := dateOf(t)
+	for range searchDays {
+		sessions := sessionsOn(date)
+		for i := len(sessions) - 1; i >= 0; i-- {
+			if !sessions[i].Start().After(t) {
+				return sessions[i], true
+			}
+		}
+		date = date.AddDate(0, 0, -1)
+	}
+	return Session{}, false
+}
+
+// nextSession returns the first session starting after t.
+func nextSession(t time.Time) (Session, bool) {
+	date := dateOf(t)
+	for range searchDays {
+		for _, s := range sessionsOn(date) {
+			if s.Start().After(t) {
+				return s, true
+			}
+		}
+		date = date.AddDate(0, 0, 1)
+	}
+	return Session{}, false
+}
+
+// resolveSelector finds the session the selector refers to at now.
+func resolveSelector(selector string, now time.Time) (Session, error) {
+	switch selector {
+	case SelectorNow:
+		if s, ok := latestSession(now); ok && now.Before(s.End()) {
+			return s, nil
+		}
+		return Session{}, fmt.Errorf("no ADT session is being held now (%s); %s", now.In(jst).Format("2006-01-02 15:04 MST"), nearestSessions(now))
+	case SelectorLatest:
+		if s, ok := latestSession(now); ok {
+			return s, nil
+		}
+	case SelectorNext:
+		if s, ok := nextSession(now); ok {
+			return s, nil
+		}
+	default:
+		return Session{}, fmt.Errorf("unknown selector %q (expected %s, %s or %s)", selector, SelectorNow, SelectorNext, SelectorLatest)
+	}
+	return Session{}, fmt.Errorf("no ADT session found for %q within %d days", selector, searchDays)
+}
+
+// nearestSessions describes the sessions around t for error messages.
+func nearestSessions(t time.Time) string {
+	var nearest []string
+	if s, ok := latestSession(t); ok {
+		nearest = append(nearest, "latest: "+s.String())
+	}
+	if s, ok := nextSession(t); ok {
+		nearest = append(nearest, "next: "+s.String())
+	}
+	if len(nearest) == 0 {
+		return "no sessions are scheduled"
+	}
+	return "nearest sessions are " + strings.Join(nearest, ", ")
+}
@@ -0,0 +1,41 @@
+package agc
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/cry999/atcoder-cli/contests"
+)
+
+func init() {
+	contests.Register(contests.Registration{
+		Name:  "agc",
+		Usage: "<number> [task]",
+		Help:  "AtCoder Grand Contest",
+		Parse: func(args []string) (contests.Family, error) {
+			return New(contests.Arg(args, 0))
+		},
+		TaskIndexArg: contests.ArgAt(1),
+	})
+}
+
+// New creates a new AGC contest family from the contest number (e.g. "068").
+func New(rawNumber string) (contests.Family, error) {
+	number, err := contests.ParseContestNumber("agc", rawNumber)
+	if err != nil {
+		return nil, err
+	}
+	return &family{number: number}, nil
+}
+
+type family struct {
+	number int
+}
+
+func (f *family) ContestName() string {
+	return fmt.Sprintf("agc%03d", f.number)
+}
+
+func (f *family) BaseDir(workdir string) string {
+	return filepath.Join(workdir, "agc", fmt.Sprintf("%03d", f.number))
+}
@@ -0,0 +1,46 @@
+package ahc
+
+import "fmt"
+
+// Config represents the configuration specific to AtCoder Heuristic Contests.
+type Config struct {
+	// Build is the command building the local tools, run in the tools
+	// directory before scoring.
+	Build []string `toml:"build"`
+	// Scorer is the command evaluating an output, run in the tools
+	// directory. "{input}" and "{output}" are replaced with the file paths.
+	Scorer []string `toml:"scorer"`
+	// Objective is whether a higher ("max") or lower ("min") score is better.
+	Objective Objective `toml:"objective"`
+	// Jobs is the number of seeds evaluated in parallel. Zero means the
+	// number of CPUs.
+	Jobs int `toml:"jobs,omitempty"`
+}
+
+// Objective represents the direction of the score of a heuristic contest.
+type Objective string
+
+// Possible values for Objective.
+var (
+	ObjectiveMax Objective = "max"
+	ObjectiveMin Objective = "min"
+)
+
+// Validate reports an error for a value which would be ignored silently.
+func (c Config) Validate() error {
+	switch c.Objective {
+	case ObjectiveMax, ObjectiveMin:
+		return nil
+	}
+	return fmt.Errorf("unknown objective %q (expected %s or %s)", c.Objective, ObjectiveMax, ObjectiveMin)
+}
+
+// DefaultConfig returns the configuration for the Rust local tools shipped
+// with most AHCs.
+func DefaultConfig() Config {
+	return Config{
+		Build:     []string{"cargo", "build", "--release", "--bin", "vis"},
+		Scorer:    []string{"target/release/vis", "{input}", "{output}"},
+		Objective: ObjectiveMax,
+	}
+}
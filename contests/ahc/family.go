@@ -0,0 +1,50 @@
+package ahc
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/cry999/atcoder-cli/contests"
+)
+
+func init() {
+	contests.Register(contests.Registration{
+		Name:  "ahc",
+		Usage: "<number> [task]",
+		Help:  "AtCoder Heuristic Contest (tested by score with the local tools)",
+		Parse: func(args []string) (contests.Family, error) {
+			return New(contests.Arg(args, 0))
+		},
+		TaskIndexArg: contests.ArgAt(1),
+	})
+}
+
+// New creates a new AHC contest family from the contest number (e.g. "030").
+func New(rawNumber string) (contests.Family, error) {
+	number, err := contests.ParseContestNumber("ahc", rawNumber)
+	if err != nil {
+		return nil, err
+	}
+	return &family{number: number}, nil
+}
+
+type family struct {
+	number int
+}
+
+func (f *family) ContestName() string {
+	return fmt.Sprintf("ahc%03d", f.number)
+}
+
+func (f *family) BaseDir(workdir string) string {
+	return filepath.Join(workdir, "ahc", fmt.Sprintf("%03d", f.number))
+}
+
+func (f *family) Heuristic() bool {
+	return true
+}
+
+// TaskIndex returns the only task of an AHC.
+func (f *family) TaskIndex() string {
+	return "A"
+}
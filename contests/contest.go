@@ -0,0 +1,109 @@
+package contests
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Contest is the metadata of a contest.
+type Contest struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+	// StartAt is the start time of the contest. Zero if unknown.
+	StartAt time.Time `json:"start_at"`
+	// Duration is the length of the contest. Zero if unknown.
+	Duration time.Duration `json:"duration"`
+	// Penalty is the time added to the score time per rejected submission.
+	Penalty time.Duration `json:"penalty"`
+	// RatedRange is the rating range rated by the contest (e.g. "- 1999").
+	RatedRange string `json:"rated_range,omitempty"`
+	Tasks      []Task `json:"tasks,omitempty"`
+}
+
+// contestJSON is the form of Contest in contest.json, with the durations
+// written as strings such as "1h40m0s" rather than nanoseconds.
+type contestJSON struct {
+	ID         string       `json:"id"`
+	Title      string       `json:"title"`
+	StartAt    time.Time    `json:"start_at"`
+	Duration   jsonDuration `json:"duration"`
+	Penalty    jsonDuration `json:"penalty"`
+	RatedRange string       `json:"rated_range,omitempty"`
+	Tasks      []Task       `json:"tasks,omitempty"`
+}
+
+func (c Contest) MarshalJSON() ([]byte, error) {
+	return json.Marshal(contestJSON{
+		ID:         c.ID,
+		Title:      c.Title,
+		StartAt:    c.StartAt,
+		Duration:   jsonDuration(c.Duration),
+		Penalty:    jsonDuration(c.Penalty),
+		RatedRange: c.RatedRange,
+		Tasks:      c.Tasks,
+	})
+}
+
+func (c *Contest) UnmarshalJSON(data []byte) error {
+	var v contestJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*c = Contest{
+		ID:         v.ID,
+		Title:      v.Title,
+		StartAt:    v.StartAt,
+		Duration:   time.Duration(v.Duration),
+		Penalty:    time.Duration(v.Penalty),
+		RatedRange: v.RatedRange,
+		Tasks:      v.Tasks,
+	}
+	return nil
+}
+
+// jsonDuration is a duration written as a string. Numbers of nanoseconds
+// written by older versions are also accepted.
+type jsonDuration time.Duration
+
+func (d jsonDuration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+func (d *jsonDuration) UnmarshalJSON(data []byte) error {
+	var n int64
+	if err := json.Unmarshal(data, &n); err == nil {
+		*d = jsonDuration(n)
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("invalid duration %s", data)
+	}
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = jsonDuration(v)
+	return nil
+}
+
+// Task is the metadata of a task of a contest.
+type Task struct {
+	Index string `json:"index"`
+	ID    string `json:"id"`
+}
+
+// EndAt returns the end time of the contest. Zero if unknown.
+func (c *Contest) EndAt() time.Time {
+	if c.StartAt.IsZero() || c.Duration == 0 {
+		return time.Time{}
+	}
+	return c.StartAt.Add(c.Duration)
+}
+
+// Scheduled is implemented by families which know when the contest is held
+// without fetching it, such as ADT.
+type Scheduled interface {
+	Schedule() (startAt time.Time, duration time.Duration)
+}
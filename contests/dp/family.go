@@ -6,6 +6,18 @@ import (
 	"github.com/cry999/atcoder-cli/contests"
 )
 
+func init() {
+	contests.Register(contests.Registration{
+		Name:  "dp",
+		Usage: "[task]",
+		Help:  "Educational DP Contest",
+		Parse: func(args []string) (contests.Family, error) {
+			return New()
+		},
+		TaskIndexArg: contests.ArgAt(0),
+	})
+}
+
 // New creates a new DP contest family.
 func New() (contests.Family, error) {
 	return &family{}, nil
@@ -1,6 +1,66 @@
 package contests
 
+import "strings"
+
 type Family interface {
 	ContestName() string
 	BaseDir(workdir string) string
 }
+
+// TaskIndexNormalizer is implemented by families whose task indexes, as
+// typed on the command line, differ from the ones shown on AtCoder.
+type TaskIndexNormalizer interface {
+	NormalizeTaskIndex(index string) string
+}
+
+// NormalizeTaskIndex converts a task index given by the user into the form
+// used for the task directories of the family.
+func NormalizeTaskIndex(family Family, index string) string {
+	if n, ok := family.(TaskIndexNormalizer); ok {
+		return n.NormalizeTaskIndex(index)
+	}
+	return strings.ToUpper(index)
+}
+
+// TaskIndexProvider is implemented by families that already know which task
+// the user is interested in, e.g. one created from a task URL. TaskIndex may
+// return a task ID (e.g. "abc350_d") instead of a task index.
+type TaskIndexProvider interface {
+	TaskIndex() string
+}
+
+// Alias is implemented by families which may stand for a contest of a
+// dedicated family, e.g. a contest given by its ID "abc350". Resolve uses the
+// dedicated family instead, so that the contest has a single working
+// directory and the features of the family.
+type Alias interface {
+	Dedicated() (Family, bool)
+}
+
+// Group is implemented by families made of several contests, such as
+// multiple ADT levels of the same session. Commands handle each member as an
+// individual family.
+type Group interface {
+	Family
+	Members() []Family
+}
+
+// Members returns the members of family if it is a Group, or family itself.
+func Members(family Family) []Family {
+	if g, ok := family.(Group); ok {
+		return g.Members()
+	}
+	return []Family{family}
+}
+
+// Heuristic is implemented by families of heuristic contests, whose tasks
+// are evaluated by score with the local tools instead of by sample outputs.
+type Heuristic interface {
+	Heuristic() bool
+}
+
+// IsHeuristic reports whether family is a heuristic contest.
+func IsHeuristic(family Family) bool {
+	h, ok := family.(Heuristic)
+	return ok && h.Heuristic()
+}
@@ -0,0 +1,129 @@
+package generic
+
+import (
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/cry999/atcoder-cli/contests"
+	"github.com/cry999/atcoder-cli/contests/abc"
+	"github.com/cry999/atcoder-cli/contests/agc"
+	"github.com/cry999/atcoder-cli/contests/ahc"
+	"github.com/cry999/atcoder-cli/contests/arc"
+	"github.com/cry999/atcoder-cli/contests/practice"
+)
+
+var contestIDPattern = regexp.MustCompile(`^[0-9A-Za-z_-]+$`)
+
+// numberedIDPattern matches the IDs of the contests of the numbered families,
+// e.g. "abc350".
+var numberedIDPattern = regexp.MustCompile(`^(abc|arc|agc|ahc)(\d+)$`)
+
+// numberedFamilies create the numbered families from the contest numbers.
+var numberedFamilies = map[string]func(rawNumber string) (contests.Family, error){
+	"abc": abc.New,
+	"arc": arc.New,
+	"agc": agc.New,
+	"ahc": ahc.New,
+}
+
+func init() {
+	contests.Register(contests.Registration{
+		Name:  "contest",
+		Usage: "<contest id|url> [task]",
+		Help:  "Any contest, given by its contest ID or URL (used for unknown family names)",
+		Parse: func(args []string) (contests.Family, error) {
+			f, err := New(contests.Arg(args, 0))
+			if err != nil {
+				return nil, err
+			}
+			return f, nil
+		},
+		TaskIndexArg: contests.ArgAt(1),
+		Fallback:     true,
+	})
+}
+
+// Family is a contest family for an arbitrary AtCoder contest, identified by
+// its contest ID (e.g. "typical90") or by a URL of one of its pages.
+type Family struct {
+	contest string
+	taskID  string
+}
+
+// New creates a new generic contest family. raw is either a contest ID or a
+// URL such as "https://atcoder.jp/contests/abc350/tasks/abc350_d".
+func New(raw string) (*Family, error) {
+	if contestIDPattern.MatchString(raw) {
+		return &Family{contest: raw}, nil
+	}
+
+	if !strings.Contains(raw, "://") && !strings.HasPrefix(raw, "/") {
+		raw = "https://" + raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid contest URL %q: %w", raw, err)
+	}
+
+	// /contests/{contest}/tasks/{task}
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(segments) < 2 || segments[0] != "contests" || !contestIDPattern.MatchString(segments[1]) {
+		return nil, fmt.Errorf("not an AtCoder contest URL: %q", raw)
+	}
+	f := &Family{contest: segments[1]}
+	if len(segments) >= 4 && segments[2] == "tasks" && contestIDPattern.MatchString(segments[3]) {
+		f.taskID = segments[3]
+	}
+	return f, nil
+}
+
+func (f *Family) ContestName() string {
+	return f.contest
+}
+
+func (f *Family) BaseDir(workdir string) string {
+	return filepath.Join(workdir, f.contest)
+}
+
+// Dedicated returns the family dedicated to the contest, such as the ABC
+// family for "abc350" or the practice family for "typical90".
+func (f *Family) Dedicated() (contests.Family, bool) {
+	var (
+		family contests.Family
+		err    error
+	)
+	if m := numberedIDPattern.FindStringSubmatch(f.contest); m != nil {
+		family, err = numberedFamilies[m[1]](m[2])
+	} else {
+		family, err = practice.New(f.contest)
+	}
+	// "abc1" などは abc001 とは別のコンテストなので、ID が一致する場合だけ使う
+	if err != nil || family.ContestName() != f.contest {
+		return nil, false
+	}
+	return family, true
+}
+
+// TaskID returns the task ID (e.g. "abc350_d") if the family was created from
+// a task URL.
+func (f *Family) TaskID() string {
+	return f.taskID
+}
+
+// TaskIndex returns the task ID, which commands resolve to the task index
+// with the task list, or "" if the family was not created from a task URL.
+func (f *Family) TaskIndex() string {
+	return f.taskID
+}
+
+// NormalizeTaskIndex guesses the task index from a task ID (e.g. "D" for
+// "abc350_d") when the task list is not available.
+func (f *Family) NormalizeTaskIndex(index string) string {
+	if i := strings.LastIndex(index, "_"); i >= 0 {
+		index = index[i+1:]
+	}
+	return strings.ToUpper(index)
+}
@@ -0,0 +1,21 @@
+package contests
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ParseContestNumber parses the number of a numbered contest such as ABC,
+// ARC or AGC. Both "350" and "abc350" are accepted for prefix "abc".
+func ParseContestNumber(prefix, raw string) (int, error) {
+	number, err := strconv.Atoi(strings.TrimPrefix(strings.ToLower(raw), prefix))
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s number %q: %w", strings.ToUpper(prefix), raw, err)
+	}
+	if number <= 0 {
+		return 0, errors.New(strings.ToUpper(prefix) + " number must be positive")
+	}
+	return number, nil
+}
@@ -0,0 +1,77 @@
+package practice
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/cry999/atcoder-cli/contests"
+)
+
+// set describes a practice or textbook contest.
+type set struct {
+	name string
+	help string
+	// width is the number of digits of the numeric part of the task indexes.
+	width int
+}
+
+var sets = []set{
+	{name: "typical90", help: "Typical 90 problems for competitive programming (001-090)", width: 3},
+	{name: "tessoku-book", help: "Tessoku book exercises (A01-C20)", width: 2},
+	{name: "math-and-algorithm", help: "Math and algorithm exercises (001-104)", width: 3},
+}
+
+func init() {
+	for _, s := range sets {
+		contests.Register(contests.Registration{
+			Name:  s.name,
+			Usage: "[task]",
+			Help:  s.help,
+			Parse: func(args []string) (contests.Family, error) {
+				return New(s.name)
+			},
+			TaskIndexArg: contests.ArgAt(0),
+		})
+	}
+}
+
+// New creates a new practice contest family by the contest name (e.g.
+// "typical90").
+func New(name string) (contests.Family, error) {
+	for _, s := range sets {
+		if s.name == name {
+			return &family{set: s}, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown practice contest %q", name)
+}
+
+type family struct {
+	set set
+}
+
+func (f *family) ContestName() string {
+	return f.set.name
+}
+
+func (f *family) BaseDir(workdir string) string {
+	return filepath.Join(workdir, f.set.name)
+}
+
+// NormalizeTaskIndex upper-cases the letter part and zero-pads the numeric
+// part of index, e.g. "1" to "001" for typical90 and "a1" to "A01" for
+// tessoku-book.
+func (f *family) NormalizeTaskIndex(index string) string {
+	digits := strings.TrimLeftFunc(index, func(r rune) bool { return !unicode.IsDigit(r) })
+	prefix := strings.ToUpper(strings.TrimSuffix(index, digits))
+	if digits == "" || strings.IndexFunc(digits, func(r rune) bool { return !unicode.IsDigit(r) }) >= 0 {
+		return strings.ToUpper(index)
+	}
+	digits = strings.TrimLeft(digits, "0")
+	if len(digits) < f.set.width {
+		digits = strings.Repeat("0", f.set.width-len(digits)) + digits
+	}
+	return prefix + digits
+}
@@ -0,0 +1,176 @@
+package contests
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Registration describes how a contest family is selected on the command
+// line.
+type Registration struct {
+	// Name is the name of the family as typed on the command line (e.g. "abc").
+	Name string
+	// Usage describes the arguments following the name (e.g. "<number> [task]").
+	Usage string
+	// Help is a short description of the family.
+	Help string
+	// Flags registers the family specific flags, if any.
+	Flags func(fs *flag.FlagSet)
+	// Parse creates the family from the arguments following the name.
+	Parse func(args []string) (Family, error)
+	// TaskIndexArg returns the position of the task index in the arguments
+	// following the name, or -1 if the family does not take one.
+	TaskIndexArg func(args []string) int
+	// Fallback marks the family used when no family is registered with the
+	// given name. The name itself is then passed as the first argument.
+	Fallback bool
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]Registration{}
+	// familyFlags are the names of the flags registered by each family.
+	familyFlags = map[string][]string{}
+)
+
+// Register makes a contest family available by the provided name. It panics
+// if the name is registered twice or if Parse is nil.
+func Register(r Registration) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if r.Parse == nil {
+		panic("contests: Register parse is nil for " + r.Name)
+	}
+	if _, dup := registry[r.Name]; dup {
+		panic("contests: Register called twice for family " + r.Name)
+	}
+	if r.TaskIndexArg == nil {
+		r.TaskIndexArg = ArgAt(-1)
+	}
+	registry[r.Name] = r
+}
+
+// Lookup returns the family registered by the provided name.
+func Lookup(name string) (Registration, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	r, ok := registry[name]
+	return r, ok
+}
+
+// Registrations returns all registered families sorted by name.
+func Registrations() []Registration {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	rs := make([]Registration, 0, len(registry))
+	for _, r := range registry {
+		rs = append(rs, r)
+	}
+	slices.SortFunc(rs, func(a, b Registration) int { return strings.Compare(a.Name, b.Name) })
+	return rs
+}
+
+// RegisterFlags registers the flags of all registered families to fs.
+func RegisterFlags(fs *flag.FlagSet) {
+	for _, r := range Registrations() {
+		if r.Flags == nil {
+			continue
+		}
+		known := map[string]bool{}
+		fs.VisitAll(func(f *flag.Flag) { known[f.Name] = true })
+		r.Flags(fs)
+		var names []string
+		fs.VisitAll(func(f *flag.Flag) {
+			if !known[f.Name] {
+				names = append(names, f.Name)
+			}
+		})
+
+		registryMu.Lock()
+		familyFlags[r.Name] = names
+		registryMu.Unlock()
+	}
+}
+
+// CheckFlags returns an error if a flag of a family other than the one
+// selected by name is set in fs, since it would be ignored silently.
+func CheckFlags(fs *flag.FlagSet, name string) error {
+	if _, ok := Lookup(name); !ok {
+		if fallback, found := fallbackRegistration(); found {
+			name = fallback.Name
+		}
+	}
+
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	var errs []error
+	fs.Visit(func(f *flag.Flag) {
+		for family, names := range familyFlags {
+			if family != name && slices.Contains(names, f.Name) {
+				errs = append(errs, fmt.Errorf("-%s is only for the %s family", f.Name, family))
+			}
+		}
+	})
+	return errors.Join(errs...)
+}
+
+// Resolve creates the family registered by name from args, the arguments
+// following the name. It also returns the task index given in args, if any.
+func Resolve(name string, args []string) (Family, string, error) {
+	r, ok := Lookup(name)
+	if !ok {
+		fallback, found := fallbackRegistration()
+		if !found {
+			return nil, "", fmt.Errorf("unknown contest family %q", name)
+		}
+		r, args = fallback, append([]string{name}, args...)
+	}
+
+	family, err := r.Parse(args)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to parse %s family: %w", r.Name, err)
+	}
+	taskIndex := Arg(args, r.TaskIndexArg(args))
+	if p, ok := family.(TaskIndexProvider); ok && taskIndex == "" {
+		taskIndex = p.TaskIndex()
+	}
+	if a, ok := family.(Alias); ok {
+		if dedicated, ok := a.Dedicated(); ok {
+			family = dedicated
+			if p, ok := family.(TaskIndexProvider); ok && taskIndex == "" {
+				taskIndex = p.TaskIndex()
+			}
+		}
+	}
+	return family, taskIndex, nil
+}
+
+func fallbackRegistration() (Registration, bool) {
+	for _, r := range Registrations() {
+		if r.Fallback {
+			return r, true
+		}
+	}
+	return Registration{}, false
+}
+
+// ArgAt returns a TaskIndexArg function reporting the fixed position i.
+func ArgAt(i int) func(args []string) int {
+	return func([]string) int { return i }
+}
+
+// Arg returns args[i], or "" if i is out of range.
+func Arg(args []string, i int) string {
+	if i < 0 || i >= len(args) {
+		return ""
+	}
+	return args[i]
+}